@@ -0,0 +1,62 @@
+package ctimefmt
+
+import "sync"
+
+// cacheSize bounds the number of formats a cache holds, so that callers
+// passing arbitrary formats cannot grow it without limit.
+const cacheSize = 1024
+
+// cache is a concurrency-safe map from ctime-like formats to the result
+// of converting them. Once full, an arbitrary entry is evicted for each
+// new one.
+type cache struct {
+	mu sync.RWMutex
+	m  map[string]interface{}
+}
+
+var (
+	nativeCache = &cache{}
+	layoutCache = &cache{}
+)
+
+func (c *cache) load(format string) (interface{}, bool) {
+	c.mu.RLock()
+	v, ok := c.m[format]
+	c.mu.RUnlock()
+	return v, ok
+}
+
+func (c *cache) store(format string, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.m == nil {
+		c.m = make(map[string]interface{})
+	}
+	if _, ok := c.m[format]; !ok && len(c.m) >= cacheSize {
+		for k := range c.m {
+			delete(c.m, k)
+			break
+		}
+	}
+	c.m[format] = v
+}
+
+func (c *cache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.m)
+}
+
+// compile returns the layout of format, converting it only if it is not
+// cached yet.
+func compile(format string) (*Layout, error) {
+	if l, ok := layoutCache.load(format); ok {
+		return l.(*Layout), nil
+	}
+	l, err := Compile(format)
+	if err != nil {
+		return nil, err
+	}
+	layoutCache.store(format, l)
+	return l, nil
+}
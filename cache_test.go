@@ -0,0 +1,83 @@
+package ctimefmt
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestCacheBound(t *testing.T) {
+	c := &cache{}
+	for i := 0; i < 2*cacheSize; i++ {
+		c.store(strconv.Itoa(i), i)
+	}
+	if n := c.len(); n != cacheSize {
+		t.Errorf("Given: %v, expected: %v", n, cacheSize)
+	}
+	if v, ok := c.load(strconv.Itoa(2*cacheSize - 1)); !ok || v != 2*cacheSize-1 {
+		t.Errorf("Given: %v, expected: %v", v, 2*cacheSize-1)
+	}
+}
+
+func TestToNativeCached(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				native, err := ToNative(format1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if native != "2006-01-02 15:04:05.999999" {
+					t.Errorf("Given: %v, expected: %v", native, "2006-01-02 15:04:05.999999")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Errors are not cached.
+	for i := 0; i < 2; i++ {
+		if _, err := ToNative("%v"); err == nil {
+			t.Error("ToNative: expected an error for an unsupported directive")
+		}
+	}
+}
+
+func BenchmarkToNative(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ToNative(format1)
+		}
+	})
+}
+
+func BenchmarkToNativeUncached(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			toNative(ctimeSubstitutes, format1, false)
+		}
+	})
+}
+
+func BenchmarkAppendNative(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf, _ = AppendNative(buf[:0], format1)
+	}
+}
+
+func BenchmarkToNativeBytes(b *testing.B) {
+	b.ReportAllocs()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		native, _ := ToNative(format1)
+		buf = []byte(native)
+	}
+	_ = buf
+}
@@ -0,0 +1,150 @@
+package ctimefmt
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Converter converts ctime-like formats to Go layouts with its own table
+// of directive substitutes, e.g. to support several dialects of directives
+// in one program without changing the package-level behavior.
+type Converter struct {
+	substitutes map[string]string
+	natives     *cache
+}
+
+// Dialect selects how a Converter interprets the few directives which
+// differ between the strftime implementations of C, Python and Ruby.
+type Dialect int
+
+// The dialects of NewDialect. C is that of ToNative, New and Format,
+// except that its %c space-pads the day, as the C locale of glibc does.
+//
+//	Directive  C                       Python                  Ruby
+//	%f         microseconds, trimmed   microseconds            unsupported
+//	%L         milliseconds            unsupported             milliseconds
+//	%N         nanoseconds             unsupported             nanoseconds
+//	%3N        unsupported             unsupported             milliseconds, also %6N and %9N
+//	%c         %a %b %e %H:%M:%S %Y    %a %b %e %H:%M:%S %Y    %a %b %e %H:%M:%S %Y
+const (
+	C Dialect = iota
+	Python
+	Ruby
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case C:
+		return "C"
+	case Python:
+		return "Python"
+	case Ruby:
+		return "Ruby"
+	}
+	return "Dialect(" + strconv.Itoa(int(d)) + ")"
+}
+
+// dialect -> substitutes replacing those of ctimeSubstitutes, where an
+// empty layout removes the directive.
+var dialectSubstitutes = map[Dialect]map[string]string{
+	C: {
+		"%c": "Mon Jan _2 15:04:05 2006",
+	},
+	Python: {
+		"%f": "000000",
+		"%L": "",
+		"%N": "",
+		"%c": "Mon Jan _2 15:04:05 2006",
+	},
+	Ruby: {
+		"%f":  "",
+		"%3N": "000",
+		"%6N": "000000",
+		"%9N": "000000000",
+		"%c":  "Mon Jan _2 15:04:05 2006",
+	},
+}
+
+// New returns a Converter which converts the directives of ToNative, with
+// those of extra added or replaced. The keys of extra are directives, e.g.
+// "%K" or "%D", and the values the Go layouts they convert to, e.g. "15"
+// or "01/02/06".
+//
+// An error is returned if a key is not a single directive or a value is
+// empty.
+func New(extra map[string]string) (*Converter, error) {
+	return newConverter(nil, extra)
+}
+
+// NewDialect is like New but starts from the directives of dialect, e.g.
+// Python for %f to convert to six digits of microseconds.
+func NewDialect(dialect Dialect, extra map[string]string) (*Converter, error) {
+	dialectSubsts, ok := dialectSubstitutes[dialect]
+	if !ok {
+		return nil, fmt.Errorf("unknown dialect %v", dialect)
+	}
+	return newConverter(dialectSubsts, extra)
+}
+
+// newConverter returns a Converter with the substitutes of ToNative,
+// replaced by those of dialectSubsts and then of extra.
+func newConverter(dialectSubsts, extra map[string]string) (*Converter, error) {
+	substitutes := make(map[string]string, len(ctimeSubstitutes)+len(dialectSubsts)+len(extra))
+	for directive, native := range ctimeSubstitutes {
+		substitutes[directive] = native
+	}
+	for directive, native := range dialectSubsts {
+		if native == "" {
+			delete(substitutes, directive)
+		} else {
+			substitutes[directive] = native
+		}
+	}
+	for directive, native := range extra {
+		if start, end := nextDirective(directive, 0); start != 0 || end != len(directive) {
+			return nil, fmt.Errorf("invalid directive %q, expected a single directive", directive)
+		}
+		if native == "" {
+			return nil, fmt.Errorf("directive %q has an empty layout", directive)
+		}
+		substitutes[directive] = native
+	}
+	return &Converter{substitutes: substitutes, natives: &cache{}}, nil
+}
+
+// ToNative is like the package-level ToNative but converts the directives
+// of c.
+func (c *Converter) ToNative(format string) (string, error) {
+	if native, ok := c.natives.load(format); ok {
+		return native.(string), nil
+	}
+	native, err := toNative(c.substitutes, format, false)
+	if err != nil {
+		return "", err
+	}
+	c.natives.store(format, native)
+	return native, nil
+}
+
+// Format formats t with the Go layout c converts format to. Unlike the
+// package-level Format, it only supports directives with a Go layout
+// equivalent.
+func (c *Converter) Format(format string, t time.Time) (string, error) {
+	native, err := c.ToNative(format)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(native), nil
+}
+
+// Parse parses value with the Go layout c converts format to, as
+// time.Parse does. Unlike the package-level Parse, it only supports
+// directives with a Go layout equivalent.
+func (c *Converter) Parse(format, value string) (time.Time, error) {
+	native, err := c.ToNative(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(native, value)
+}
@@ -0,0 +1,158 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConverter(t *testing.T) {
+	posix, err := New(map[string]string{"%D": "01/02/06", "%K": "15"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gnu, err := New(map[string]string{"%K": "3PM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dt := time.Date(2021, 3, 4, 17, 6, 7, 0, time.UTC)
+	tests := []struct {
+		c        *Converter
+		format   string
+		native   string
+		expected string
+	}{
+		{posix, "%D %K:%M", "01/02/06 15:04", "03/04/21 17:06"},
+		{gnu, "%D %K", "01/02/2006 3PM", "03/04/2021 5PM"},
+	}
+	for _, test := range tests {
+		native, err := test.c.ToNative(test.format)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if native != test.native {
+			t.Errorf("Given: %v, expected: %v", native, test.native)
+		}
+		s, err := test.c.Format(test.format, dt)
+		if err != nil {
+			t.Error(err)
+		} else if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+		if _, err := test.c.Parse(test.format, test.expected); err != nil {
+			t.Error(err)
+		}
+	}
+
+	parsed, err := posix.Parse("%Y-%m-%d %K:%M", "2021-03-04 17:06")
+	if err != nil {
+		t.Error(err)
+	} else if expected := dt.Add(-7 * time.Second); !parsed.Equal(expected) {
+		t.Errorf("Given: %v, expected: %v", parsed, expected)
+	}
+
+	// New converts the directives of ToNative.
+	def, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"%c", "%D %T", "%H:%M:%S.%f"} {
+		native, err := def.ToNative(format)
+		expected, _ := ToNative(format)
+		if err != nil || native != expected {
+			t.Errorf("%q: given: %v, %v, expected: %v", format, native, err, expected)
+		}
+	}
+
+	// The package-level table is unchanged.
+	if native, err := ToNative("%D"); err != nil || native != "01/02/2006" {
+		t.Errorf("Given: %v, %v, expected: %v", native, err, "01/02/2006")
+	}
+	if _, err := ToNative("%K"); err == nil {
+		t.Error("ToNative: expected an error for a converter directive")
+	}
+
+	if _, err := posix.ToNative("%j"); err == nil {
+		t.Error("ToNative: expected an error for a directive without a Go layout equivalent")
+	}
+	if _, err := posix.ToNative("%K01"); err == nil {
+		t.Error("ToNative: expected an error for literal digits reading as a Go layout element")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, extra := range []map[string]string{
+		{"K": "15"},
+		{"%K%M": "15:04"},
+		{"%": "15"},
+		{"%K": ""},
+	} {
+		if _, err := New(extra); err == nil {
+			t.Errorf("New(%q): expected an error", extra)
+		}
+	}
+}
+
+func TestDialect(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 120000000, time.UTC)
+	tests := []struct {
+		dialect  Dialect
+		format   string
+		expected string
+	}{
+		{C, "%H:%M:%S.%f", "05:06:07.12"},
+		{Python, "%H:%M:%S.%f", "05:06:07.120000"},
+		{Ruby, "%H:%M:%S.%L", "05:06:07.120"},
+		{Ruby, "%H:%M:%S.%6N", "05:06:07.120000"},
+		{Ruby, "%H:%M:%S.%N", "05:06:07.120000000"},
+		{C, "%c", "Thu Mar  4 05:06:07 2021"},
+		{Python, "%c", "Thu Mar  4 05:06:07 2021"},
+		{Ruby, "%c", "Thu Mar  4 05:06:07 2021"},
+	}
+	for _, test := range tests {
+		c, err := NewDialect(test.dialect, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := c.Format(test.format, dt)
+		if err != nil {
+			t.Errorf("%v: %v", test.dialect, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%v: given: %v, expected: %v", test.dialect, s, test.expected)
+		}
+		parsed, err := c.Parse(test.format, s)
+		if err != nil {
+			t.Errorf("%v: %v", test.dialect, err)
+		} else if test.format != "%c" && parsed.Nanosecond() != dt.Nanosecond() {
+			t.Errorf("%v: given: %v, expected: %v", test.dialect, parsed.Nanosecond(), dt.Nanosecond())
+		}
+	}
+
+	// Directives a dialect does not have are unsupported.
+	unsupported := map[Dialect][]string{
+		C:      {"%3N"},
+		Python: {"%L", "%N", "%3N"},
+		Ruby:   {"%f"},
+	}
+	for dialect, formats := range unsupported {
+		c, err := NewDialect(dialect, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range formats {
+			if _, err := c.ToNative(format); err == nil {
+				t.Errorf("%v: %q: expected an error", dialect, format)
+			}
+		}
+	}
+
+	if _, err := NewDialect(Dialect(7), nil); err == nil {
+		t.Error("NewDialect: expected an error for an unknown dialect")
+	}
+	if s := Dialect(7).String(); s != "Dialect(7)" {
+		t.Errorf("Given: %v, expected: %v", s, "Dialect(7)")
+	}
+}
@@ -7,86 +7,139 @@
 package ctimefmt
 
 import (
-	"errors"
 	"fmt"
-	"regexp"
+	"io"
+	"strings"
 	"time"
 )
 
-var ctimeRegexp = regexp.MustCompile(`%.`)
-var decimalsRegexp = regexp.MustCompile(`\d`)
-
 // ctime format -> Go format conversion
 var ctimeSubstitutes map[string]string = map[string]string{
-	"%Y": "2006",
-	"%y": "06",
-	"%m": "01",
-	"%o": "_1",
-	"%q": "1",
-	"%b": "Jan",
-	"%h": "Jan",
-	"%B": "January",
-	"%d": "02",
-	"%e": "_2",
-	"%g": "2",
-	"%a": "Mon",
-	"%A": "Monday",
-	"%H": "15",
-	"%l": "3",
-	"%I": "03",
-	"%p": "PM",
-	"%P": "pm",
-	"%M": "04",
-	"%S": "05",
-	"%L": "999",
-	"%f": "999999",
-	"%s": "99999999",
-	"%Z": "MST",
-	"%z": "-0700",
-	"%w": "-070000",
-	"%i": "-07",
-	"%j": "-07:00",
-	"%k": "-07:00:00",
-	"%D": "01/02/2006",
-	"%x": "01/02/2006",
-	"%F": "2006-01-02",
-	"%T": "15:04:05",
-	"%X": "15:04:05",
-	"%r": "03:04:05 pm",
-	"%R": "15:04",
-	"%n": "\n",
-	"%t": "\t",
-	"%%": "%",
-	"%c": "Mon Jan 02 15:04:05 2006",
+	"%Y":   "2006",
+	"%y":   "06",
+	"%m":   "01",
+	"%b":   "Jan",
+	"%h":   "Jan",
+	"%B":   "January",
+	"%d":   "02",
+	"%e":   "_2",
+	"%_d":  "_2",
+	"%a":   "Mon",
+	"%A":   "Monday",
+	"%H":   "15",
+	"%I":   "03",
+	"%p":   "PM",
+	"%P":   "pm",
+	"%M":   "04",
+	"%S":   "05",
+	"%-m":  "1",
+	"%-d":  "2",
+	"%-I":  "3",
+	"%-M":  "4",
+	"%-S":  "5",
+	"%L":   "000",
+	"%N":   "000000000",
+	"%f":   "999999",
+	"%1f":  "0",
+	"%2f":  "00",
+	"%3f":  "000",
+	"%4f":  "0000",
+	"%5f":  "00000",
+	"%6f":  "000000",
+	"%7f":  "0000000",
+	"%8f":  "00000000",
+	"%9f":  "000000000",
+	"%-1f": "9",
+	"%-2f": "99",
+	"%-3f": "999",
+	"%-4f": "9999",
+	"%-5f": "99999",
+	"%-6f": "999999",
+	"%-7f": "9999999",
+	"%-8f": "99999999",
+	"%-9f": "999999999",
+	"%Z":   "MST",
+	"%z":   "-0700",
+	"%:z":  "-07:00",
+	"%::z": "-07:00:00",
+	"%#z":  "Z0700",
+	"%#:z": "Z07:00",
+	"%i":   "-07",
+	"%D":   "01/02/2006",
+	"%x":   "01/02/2006",
+	"%F":   "2006-01-02",
+	"%T":   "15:04:05",
+	"%X":   "15:04:05",
+	"%r":   "03:04:05 pm",
+	"%R":   "15:04",
+	"%n":   "\n",
+	"%t":   "\t",
+	"%%":   "%",
+	"%c":   "Mon Jan 02 15:04:05 2006",
+	"%+":   "Mon Jan _2 15:04:05 MST 2006",
 }
 
 // Format returns a textual representation of the time value formatted
 // according to ctime-like format string. Possible directives are:
-//   %Y - Year, zero-padded (0001, 0002, ..., 2019, 2020, ..., 9999)
+//   %Y - Year, zero-padded, astronomical with a minus sign before 1 (-0001, 0000, 0001, ..., 2020, ..., 9999, 10000)
+//   %:Y - Year like %Y, which Parse also accepts as two digits like %y, e.g. 2021 or 21 followed by a non-digit
 //   %y - Year, last two digits, zero-padded (01, ..., 99)
+//   %C - Century, the year divided by 100, zero-padded (00, 01, ..., 20, ..., 99)
 //   %m - Month as a decimal number (01, 02, ..., 12)
 //   %o - Month as a space-padded number ( 1, 2, ..., 12)
-//   %q - Month as a unpadded number (1,2,...,12)
+//   %q - Quarter of the year (1, 2, 3, 4)
 //   %b, %h - Abbreviated month name (Jan, Feb, ...)
 //   %B - Full month name (January, February, ...)
 //   %d - Day of the month, zero-padded (01, 02, ..., 31)
 //   %e - Day of the month, space-padded ( 1, 2, ..., 31)
-//   %g - Day of the month, unpadded (1,2,...,31)
+//   %:d - Day of the month with its English ordinal suffix (1st, 2nd, 3rd, 4th, ..., 11th, ..., 31st)
+//   %j - Day of the year, zero-padded (001, 002, ..., 366)
+//   %G - ISO 8601 week-based year, zero-padded (0001, ..., 2020, ..., 9999)
+//   %g - ISO 8601 week-based year, last two digits, zero-padded (00, ..., 99)
+//   %V - ISO 8601 week of the year, zero-padded (01, 02, ..., 53)
+//   %:V - ISO 8601 week of the year after -W, equivalent to -W%V, e.g. %G%:V for 2021-W05 (-W01, -W02, ..., -W53)
+//   %U - Week of the year starting on Sunday, zero-padded (00, 01, ..., 53)
+//   %W - Week of the year starting on Monday, zero-padded (00, 01, ..., 53)
 //   %a - Abbreviated weekday name (Sun, Mon, ...)
 //   %A - Full weekday name (Sunday, Monday, ...)
-//   %H - Hour (24-hour clock) as a zero-padded decimal number (00, ..., 24)
+//   %1a - Weekday initial (S, M, T, ..., S), which Parse accepts but ignores, as it is ambiguous
+//   %w - Weekday as a decimal number, Sunday is 0 (0, 1, ..., 6)
+//   %u - ISO 8601 weekday as a decimal number, Monday is 1 (1, 2, ..., 7)
+//   %H - Hour (24-hour clock) as a zero-padded decimal number (00, ..., 23)
 //   %I - Hour (12-hour clock) as a zero-padded decimal number (00, ..., 12)
-//   %l - Hour (12-hour clock: 0, ..., 12)
+//   %k - Hour (24-hour clock) as a space-padded decimal number ( 0, ..., 23)
+//   %l - Hour (12-hour clock) as a space-padded decimal number ( 1, ..., 12)
 //   %p - Locale’s equivalent of either AM or PM
 //   %P - Locale’s equivalent of either am or pm
+//   %:p - A.M. or P.M., which Parse accepts in any case
+//   %:P - a.m. or p.m., which Parse accepts in any case
 //   %M - Minute, zero-padded (00, 01, ..., 59)
 //   %S - Second as a zero-padded decimal number (00, 01, ..., 59)
 //   %L - Millisecond as a decimal number, zero-padded on the left (000, 001, ..., 999)
-//   %f - Microsecond as a decimal number, zero-padded on the left (000000, ..., 999999)
-//   %s - Nanosecond as a decimal number, zero-padded on the left (000000, ..., 999999)
-//   %z - UTC offset in the form ±HHMM[SS[.ffffff]] or empty(+0000, -0400)
+//   %f - Microsecond as a decimal number, trailing zeros omitted (0, 5, ..., 999999)
+//   %1f, ..., %9f - Fraction of a second with the given number of digits, e.g.
+//       %3f for milliseconds (000, ..., 999) or %6f for microseconds (000000, ..., 999999)
+//   %-1f, ..., %-9f - Fraction of a second with up to the given number of digits, trailing
+//       zeros omitted like Go's .999 layouts, e.g. %-3f (0, 5, ..., 999); %f is %-6f
+//   %N - Nanosecond as a decimal number, zero-padded on the left (000000000, ..., 999999999)
+//   %s - Seconds since the Unix epoch, 1970-01-01 00:00:00 UTC (0, ..., 1609459200, ...)
+//   %Q, %3s - Milliseconds since the Unix epoch (0, ..., 1609459200000, ...)
+//   %6s - Microseconds since the Unix epoch (0, ..., 1609459200000000, ...)
+//   %9s - Nanoseconds since the Unix epoch (0, ..., 1609459200000000000, ...)
+//   %z - UTC offset in the form ±HHMM[SS] (+0000, -0400, -001730)
+//   %:z - UTC offset in the form ±HH:MM (+00:00, -04:00, +05:30)
+//   %::z - UTC offset in the form ±HH:MM:SS (+00:00:00, -04:00:00, +05:30:00)
+//   %:::z - UTC offset with the colon-separated parts it needs, as in GNU date (+00, -04, +05:30, -00:17:30)
+//   %#z - UTC offset in the form ±HHMM, or Z for UTC (Z, -0400, +0530)
+//   %#:z - UTC offset in the form ±HH:MM, or Z for UTC as in RFC 3339 (Z, -04:00, +05:30)
+//   %Ez - UTC offset in the form ±HH:MM, which Parse accepts with or without the colon (+05:30, +0530)
+//   %Oz - UTC offset as a signed number of minutes (+0, -240, +330)
+//   %i - UTC offset hours in the form ±HH, minutes of sub-hour offsets dropped (+00, -04, +05)
+//   %-i - UTC offset as a signed number of whole hours, minutes of sub-hour offsets dropped (+0, -4, +5)
+//   %EZ - UTC offset after UTC, which Parse also accepts after GMT or alone (UTC, UTC+02:00, GMT-0500, UTC+2)
 //   %Z - Timezone name or abbreviation or empty (UTC, EST, CST)
-//   %D, %x - Short MM/DD/YY date, equivalent to %m/%d/%y
+//   %:Z - Time zone location name, or %Z for a location without one (UTC, America/New_York, Local)
+//   %D, %x - Short MM/DD/YYYY date, equivalent to %m/%d/%Y
 //   %F - Short YYYY-MM-DD date, equivalent to %Y-%m-%d
 //   %T, %X - ISO 8601 time format (HH:MM:SS), equivalent to %H:%M:%S
 //   %r - 12-hour clock time (02:55:02 pm)
@@ -95,47 +148,695 @@ var ctimeSubstitutes map[string]string = map[string]string{
 //   %t - Horizontal-tab character ('\t')
 //   %% - A % sign
 //   %c - Date and time representation (Mon Jan 02 15:04:05 2006)
+//   %+ - Date and time as printed by date(1), equivalent to %a %b %e %H:%M:%S %Z %Y
+//
+// The E and O modifiers of POSIX, e.g. %Ey and %Od, are accepted and ignored.
+//
+// The - flag turns off the padding of a numeric directive, e.g. %-d is
+// the day of the month (1, 2, ..., 31) and %-H is the hour (0, 1, ..., 23).
+// The _ flag pads it with spaces instead, e.g. %_d is the same as %e and
+// %_H the same as %k. The ^ flag converts the text of a directive to upper
+// case, e.g. %^A is the full weekday name in upper case (SUNDAY, MONDAY,
+// ...).
 func Format(format string, t time.Time) (string, error) {
-	native, err := ToNative(format)
+	l, err := compile(format)
 	if err != nil {
 		return "", err
 	}
-	return t.Format(native), nil
+	return l.Format(t), nil
+}
+
+// AppendFormat is like Format but appends the textual representation to
+// dst and returns the extended buffer, so that a buffer can be reused
+// across calls.
+func AppendFormat(dst []byte, format string, t time.Time) ([]byte, error) {
+	l, err := compile(format)
+	if err != nil {
+		return dst, err
+	}
+	return l.AppendFormat(dst, t), nil
+}
+
+// FormatTo is like Format but writes the textual representation to w, e.g.
+// a strings.Builder or a bufio.Writer, without allocating a string. It
+// returns the error of the write, if any.
+func FormatTo(w io.Writer, format string, t time.Time) error {
+	l, err := compile(format)
+	if err != nil {
+		return err
+	}
+	return l.FormatTo(w, t)
+}
+
+// FormatInLocation is like Format but renders the time value in the given
+// location, so that for example %Z and %z show the zone of loc.
+func FormatInLocation(format string, t time.Time, loc *time.Location) (string, error) {
+	return Format(format, t.In(loc))
+}
+
+// FormatUTC is like Format but renders the time value in UTC, whatever its
+// location, e.g. for a local time from time.Now.
+func FormatUTC(format string, t time.Time) (string, error) {
+	return Format(format, t.UTC())
 }
 
 // Parse parses a ctime-like formatted string (e.g. "%Y-%m-%d ...") and returns
 // the time value it represents.
 //
 // Refer to Format() function documentation for possible directives.
+//
+// A Unix epoch directive, %s, %Q, %3s, %6s or %9s, takes precedence over
+// the other date and time directives, which are then ignored. A time zone
+// directive in the same format only selects the location of the returned
+// time; without one it is UTC.
+//
+// A field out of its range, e.g. month 13, hour 24 or February 29th of a
+// common year, is an error naming the field rather than being normalized
+// as by time.Date, so no separate strict range check is needed.
+//
+// A 12-hour clock hour, %I or %l, needs %p or %P, or the dotted %:p or
+// %:P, to tell the morning from the afternoon. Parse returns an error for
+// a format with one but not the other, instead of taking every hour as AM
+// as time.Parse does.
+//
+// An ISO 8601 week date, %G or %g with %V and optionally a weekday, e.g.
+// "%G-W%V-%u" for 2021-W05-3, selects the date when the format has no
+// month, day or day-of-year. Without a weekday it is the Monday of the
+// week, e.g. "%G%:V" parses 2021-W05 as 2021-02-01.
+//
+// Like time.Parse, a fractional-seconds directive after a literal . or ,
+// accepts either separator, e.g. "%H:%M:%S.%L" parses both 05:06:07.123
+// and 05:06:07,123.
+//
+// A numeric directive with the - or _ flag accepts its value with or
+// without padding, e.g. "%Y-%-m-%-d %-H:%-M:%-S" parses both 2021-3-4 5:6:7
+// and 2021-03-04 05:06:07. Without a flag %m, %M and %S need all their
+// digits, while %d and %I, like %H, also accept one digit, e.g.
+// "%B %d, %Y at %I:%M %p" parses March 4, 2021 at 5:06 PM. ParseStrict
+// requires the padding of %d and %I.
+//
+// The UTC offset directives %z, %:z and %::z also accept Z for UTC, as in
+// RFC 3339. %z accepts ±HHMM[SS[.ffffff]] like Python's strptime, but
+// drops the fractional seconds, which a Go offset cannot hold.
+//
+// %:Z loads the named location with time.LoadLocation, so unlike %Z it
+// selects the zone rules of the location, but it depends on the time zone
+// database being available. The abbreviation %Z renders cannot be told from
+// the location name, nor the other way round.
+//
+// Month and weekday names are matched regardless of case, e.g. %b accepts
+// jan, Jan and JAN. Literal text, e.g. the "at" of "%B %d, %Y at %I:%M %p",
+// is matched case-sensitively; layouts of CompileLenient match its letters
+// regardless of case as well.
+//
+// As in time.Parse, a run of spaces in the format matches any non-empty
+// run of spaces in the value, so that "%b %e %H:%M:%S" parses both
+// "Jan  4 05:06:07" and "Jan 4 05:06:07". ParseStrict turns this off.
 func Parse(format, value string) (time.Time, error) {
-	native, err := ToNative(format)
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.Parse(value)
+}
+
+// ParseInLocation is like Parse but interprets a time without a time zone
+// directive in the given location, and resolves zone names and offsets
+// against it, as time.ParseInLocation does.
+func ParseInLocation(format, value string, loc *time.Location) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.parse(value, loc, loc, parseOptions{})
+}
+
+// ParseStrict is like Parse but requires every byte of value to match the
+// format: literal text must match exactly, without the collapsing of runs
+// of spaces Parse does, %d and %I need both their digits, e.g. 04 rather
+// than 4, and %S does not consume a fractional part unless the format has
+// a directive for it. Literal text and the AM or PM of %p and %P also
+// match case-sensitively in layouts of CompileLenient.
+func ParseStrict(format, value string) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.ParseStrict(value)
+}
+
+// ParseChecked is like Parse but returns an error if a weekday parsed with
+// %a, %A, %u or %w does not match the day of the parsed date, e.g. for
+// "Monday 2021-03-02", a Tuesday. Parse ignores the weekday, as
+// time.Parse does. Without a day in the format the weekday is not checked.
+// Weekdays parsed by more than one directive, e.g. "%A (%u)", must also
+// match each other, with or without a day.
+func ParseChecked(format, value string) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.ParseChecked(value)
+}
+
+// ParseLeapSecond is like Parse but accepts a %S second of 60, as in a
+// leap second such as 2016-12-31 23:59:60 UTC. Go times cannot hold a leap
+// second, so it rolls over to the first second of the next minute, e.g.
+// 23:59:60 becomes 00:00:00 of the next day. Parse rejects it.
+func ParseLeapSecond(format, value string) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.ParseLeapSecond(value)
+}
+
+// ParseWithYear is like Parse but the time is in the given year when the
+// format has no year directive (%Y, %y, %C, %G or %g), e.g. for a syslog
+// timestamp such as "Jan  4 05:06:07", which Parse puts in year 0. With a
+// year directive the given year is ignored.
+func ParseWithYear(format, value string, year int) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.ParseWithYear(value, year)
+}
+
+// ParseWithPivot is like Parse but a two-digit %y or %:Y year below
+// pivotYear is in the 2000s and any other in the 1900s. Parse, like
+// time.Parse, uses a pivot of 69. The pivot is in the range [0, 100],
+// where 0 puts every year in the 1900s and 100 every year in the 2000s.
+//
+// The pivot only applies when the format has neither %C nor %Y, which
+// select the century themselves.
+func ParseWithPivot(format, value string, pivotYear int) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.ParseWithPivot(value, pivotYear)
+}
+
+// ParseAny parses value with the first of formats it matches, e.g. for
+// logs mixing timestamp formats, and returns the time with the index of
+// that format. It returns an error if a format contains an unsupported
+// directive or none of them matches. Converted formats are cached, so
+// passing the same formats again is cheap.
+func ParseAny(formats []string, value string) (time.Time, int, error) {
+	for i, format := range formats {
+		l, err := compile(format)
+		if err != nil {
+			return time.Time{}, -1, err
+		}
+		if t, err := l.Parse(value); err == nil {
+			return t, i, nil
+		}
+	}
+	return time.Time{}, -1, fmt.Errorf("none of %d formats matches %q", len(formats), value)
+}
+
+// ParseTrimmed is like Parse but first trims the white space surrounding
+// value, e.g. left by splitting a log line into fields. The white space
+// within value still has to match the format.
+func ParseTrimmed(format, value string) (time.Time, error) {
+	return Parse(format, strings.TrimSpace(value))
+}
+
+// ParseCombined parses a date and a time of day from separate values, e.g.
+// the date and time columns of a log, and merges them into one time. The
+// date is taken from dateValue and the time of day from timeValue,
+// whatever other components their formats have. The time is in the zone
+// of timeValue if timeFormat has a time zone directive, else in that of
+// dateValue if dateFormat has one, and in UTC otherwise.
+func ParseCombined(dateFormat, dateValue, timeFormat, timeValue string) (time.Time, error) {
+	d, err := Parse(dateFormat, dateValue)
+	if err != nil {
+		return time.Time{}, err
+	}
+	c, err := Parse(timeFormat, timeValue)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := time.UTC
+	if fields, _ := Fields(timeFormat); fields.Has(ZoneField) {
+		loc = c.Location()
+	} else if fields, _ := Fields(dateFormat); fields.Has(ZoneField) {
+		loc = d.Location()
+	}
+	year, month, day := d.Date()
+	hour, min, sec := c.Clock()
+	return time.Date(year, month, day, hour, min, sec, c.Nanosecond(), loc), nil
+}
+
+// Validate checks that format only contains supported directives, without
+// formatting a time. It returns nil for a valid format.
+func Validate(format string) error {
+	_, err := compile(format)
+	return err
+}
+
+// Canonicalize expands the shorthand directives of format, e.g. %F and %T,
+// into the directives they stand for and drops E and O modifiers, so that
+// formats which differ only in their use of shorthands compare equal:
+// "%F %T" and "%Y-%m-%d %OH:%M:%S" both become "%Y-%m-%d %H:%M:%S".
+// Shorthands expand to the forms of this package, which may differ from
+// POSIX: %D and %x become %m/%d/%Y with a four-digit year, like Go's
+// 01/02/2006, not the %m/%d/%y of POSIX. It returns an error if the format
+// contains an unsupported directive.
+func Canonicalize(format string) (string, error) {
+	var b strings.Builder
+	if err := canonicalize(&b, format, 0); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func canonicalize(b *strings.Builder, format string, offset int) error {
+	last := 0
+	for start, end := nextDirective(format, 0); start >= 0; start, end = nextDirective(format, end) {
+		b.WriteString(format[last:start])
+		last = end
+
+		directive := stripModifier(format[start:end])
+		if _, ok := lookupDirective(directive); !ok {
+			return unsupported(format[start:end], offset+start)
+		}
+		if d, ok := ctimeDirectives[directive]; ok && d.expand != "" {
+			if err := canonicalize(b, d.expand, offset+start); err != nil {
+				return err
+			}
+			continue
+		}
+		b.WriteString(directive)
+	}
+	b.WriteString(format[last:])
+	return nil
+}
+
+// FractionalDigits returns the number of fractional second digits format
+// renders, e.g. 3 for %L, 6 for %f and 9 for %N, or 0 if it has no
+// fractional-seconds directive. For %f, which omits trailing zeros, it is
+// the maximum number of digits.
+func FractionalDigits(format string) (int, error) {
+	l, err := compile(format)
+	if err != nil {
+		return 0, err
+	}
+	digits := 0
+	for _, c := range l.chunks {
+		if c.d != nil && c.d.frac > digits {
+			digits = c.d.frac
+		}
+	}
+	return digits, nil
+}
+
+// ctime format -> maximum length of its text, for the directives of
+// ctimeDirectives which are neither numeric nor fractional seconds and
+// whose text is bounded. Offsets are assumed to be less than 24 hours.
+var maxLengths = map[string]int{
+	"%:Y":   4,
+	"%b":    3,
+	"%h":    3,
+	"%B":    9, // September
+	"%:d":   4, // 22nd
+	"%a":    3,
+	"%A":    9, // Wednesday
+	"%1a":   1,
+	"%p":    2,
+	"%P":    2,
+	"%:p":   4,
+	"%:P":   4,
+	"%s":    20, // -9223372036854775808
+	"%Q":    20,
+	"%3s":   20,
+	"%6s":   20,
+	"%9s":   20,
+	"%z":    7, // -073015
+	"%:z":   6,
+	"%::z":  9,
+	"%:::z": 9,
+	"%#z":   5,
+	"%#:z":  6,
+	"%Ez":   6,
+	"%Oz":   5, // -1439
+	"%EZ":   9, // UTC+05:30
+	"%i":    3,
+	"%-i":   3,
+}
+
+// MaxFormattedLen returns an upper bound on the number of bytes Format
+// renders for format, e.g. 9 for %A as in Wednesday, so that callers can
+// size the buffer of AppendFormat. The bound assumes years 1 through 9999,
+// the range of the four digits of %Y.
+//
+// An error is returned if the format contains an unsupported directive,
+// or one whose text has no bound: %Z and %:Z, as zone names can have any
+// length, and custom directives.
+func MaxFormattedLen(format string) (int, error) {
+	l, err := compile(format)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, c := range l.chunks {
+		switch {
+		case c.d == nil:
+			n += len(c.text)
+		case c.d.get != nil:
+			n += c.d.width
+		case c.d.frac > 0:
+			n += c.d.frac
+			if c.sep != 0 {
+				n++
+			}
+		default:
+			text := stripModifier(c.text)
+			if text[1] == '^' {
+				text = "%" + text[2:]
+			}
+			max, ok := maxLengths[text]
+			if !ok {
+				return 0, fmt.Errorf("directive %q has no maximum length", c.text)
+			}
+			n += max
+		}
+	}
+	return n, nil
+}
+
+// Roundtrip formats t and parses the result back with the same format,
+// returning the reconstructed time. A time without a time zone directive
+// is parsed in the location of t. Comparing the result to t reveals what
+// the format loses, e.g. the year for a format without %Y, or the
+// fractional seconds for one without %L, %f or %N.
+func Roundtrip(format string, t time.Time) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.parse(l.Format(t), t.Location(), t.Location(), parseOptions{})
+}
+
+// WouldTruncate reports whether formatting the time parsed from value
+// with format drops sub-second precision the value has, e.g. the .123 of
+// "2021-03-04 05:06:07.123" for "%Y-%m-%d %H:%M:%S", whose %S parses a
+// fraction but does not render it. Use it to catch a format which does
+// not match the data. It returns an error if value does not parse.
+func WouldTruncate(format, value string) (bool, error) {
+	l, err := compile(format)
+	if err != nil {
+		return false, err
+	}
+	t, err := l.Parse(value)
+	if err != nil {
+		return false, err
+	}
+	rt, err := l.parse(l.Format(t), t.Location(), t.Location(), parseOptions{})
 	if err != nil {
-		return time.Time{}, nil
+		return false, err
 	}
-	return time.Parse(native, value)
+	return rt.Nanosecond() != t.Nanosecond(), nil
+}
+
+// compatibilityProbes are the times Compatible formats, which vary every
+// field in width and value, e.g. one- and two-digit days and afternoon
+// hours.
+var compatibilityProbes = []time.Time{
+	time.Date(2021, 3, 4, 5, 6, 7, 123000000, time.UTC),
+	time.Date(1999, 12, 31, 23, 59, 59, 999999999, time.FixedZone("EST", -5*3600)),
+	time.Date(2010, 10, 10, 12, 30, 0, 500000000, time.FixedZone("IST", 5*3600+1800)),
+	time.Date(2000, 1, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600)),
+}
+
+// Compatible reports whether the text formatA renders can be parsed with
+// formatB, e.g. when rotating log formats. It formats a few probe times
+// with formatA and parses them with formatB, which has to succeed and to
+// give the same value for every component both formats set, see Fields.
+// Being based on probes, it is a heuristic rather than a proof.
+//
+// An error is returned if either format contains an unsupported directive.
+func Compatible(formatA, formatB string) (bool, error) {
+	a, err := compile(formatA)
+	if err != nil {
+		return false, err
+	}
+	b, err := compile(formatB)
+	if err != nil {
+		return false, err
+	}
+	fieldsA, _ := Fields(formatA)
+	fieldsB, _ := Fields(formatB)
+	common := fieldsA & fieldsB
+
+	for _, t := range compatibilityProbes {
+		text := a.Format(t)
+		p, err := b.parse(text, t.Location(), t.Location(), parseOptions{})
+		if err != nil {
+			return false, nil
+		}
+		// Compare with what the text holds of t, e.g. the truncated
+		// fraction of %L.
+		if r, err := a.parse(text, t.Location(), t.Location(), parseOptions{}); err == nil {
+			t = r
+		}
+		_, offsetP := p.Zone()
+		_, offsetT := t.Zone()
+		if common.Has(ZoneField) && offsetP != offsetT {
+			return false, nil
+		}
+		p = p.In(t.Location())
+		for _, c := range []struct {
+			field FieldSet
+			p, t  int
+		}{
+			{YearField, p.Year(), t.Year()},
+			{MonthField, int(p.Month()), int(t.Month())},
+			{DayField, p.Day(), t.Day()},
+			{HourField, p.Hour(), t.Hour()},
+			{MinuteField, p.Minute(), t.Minute()},
+			{SecondField, p.Second(), t.Second()},
+			{FractionField, p.Nanosecond(), t.Nanosecond()},
+		} {
+			if common.Has(c.field) && c.p != c.t {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
 }
 
 // ToNative converts ctime-like format string to Go native layout
 // (which is used by time.Time.Format() and time.Parse() functions).
+//
+// An error is returned if the format contains a directive which is not
+// supported, or literal text which would read as a Go layout element,
+// e.g. the "01" of "%Y-%m-01" or the "Jan" of "Jan %d". The error names
+// the offending text and its byte offset in the format. Directives such
+// as %j which have no Go layout equivalent are only supported by Format
+// and Parse, which accept any literal text. Literal letters such as the T and Z of
+// "%Y-%m-%dT%H:%M:%SZ" are kept as they are, unless they would read as an
+// element such as the "Z07" of "%SZ07", as Go layouts cannot quote them.
+//
+// %z converts to -0700, which unlike Format drops the seconds of an offset
+// such as the -001730 of a historical local mean time.
+//
+// ToNative never panics, whatever the format, e.g. one read from a
+// configuration file: it returns either a layout or an error.
+//
+// Converted formats are cached, so repeated conversions of the same
+// format are cheap.
 func ToNative(format string) (string, error) {
-	if match := decimalsRegexp.FindString(format); match != "" {
-		return "", errors.New("format string should not contain decimals")
+	if native, ok := nativeCache.load(format); ok {
+		return native.(string), nil
+	}
+	native, err := toNative(ctimeSubstitutes, format, false)
+	if err != nil {
+		return "", err
 	}
+	nativeCache.store(format, native)
+	return native, nil
+}
+
+// AppendNative is like ToNative but appends the Go layout to dst and
+// returns the extended buffer. As conversions are cached, appending the
+// layout of a format converted before does not allocate unless dst grows.
+func AppendNative(dst []byte, format string) ([]byte, error) {
+	native, err := ToNative(format)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, native...), nil
+}
+
+// nativePiece is literal text or a directive substitute in a Go layout
+// converted from a ctime-like format.
+type nativePiece struct {
+	format, native int    // offsets in the format and in the layout
+	source         string // text in the format
+	text           string // text in the layout
+}
+
+// ToNativeLenient is like ToNative but passes unsupported directives, e.g.
+// the "% " of "99% done", through to the layout as literal text. Go layouts
+// have no quoting, so literal text which would read as a Go layout element,
+// e.g. the "100" of "100% done", is still an error. Like ToNative it never
+// panics.
+func ToNativeLenient(format string) (string, error) {
+	return toNative(ctimeSubstitutes, format, true)
+}
+
+// toNative converts format with the directive substitutes, with lenient
+// set passing unsupported directives through as literal text.
+func toNative(substitutes map[string]string, format string, lenient bool) (string, error) {
+	var b strings.Builder
+	var pieces []nativePiece
+	add := func(offset int, source, text string) {
+		if text != "" {
+			pieces = append(pieces, nativePiece{offset, b.Len(), source, text})
+			b.WriteString(text)
+		}
+	}
+	last := 0
+	for start, end := nextDirective(format, 0); start >= 0; start, end = nextDirective(format, end) {
+		directive := format[start:end]
+		subst, ok := substitutes[stripModifier(directive)]
+		if !ok {
+			if _, ok := lookupDirective(directive); ok {
+				return "", directiveError(directive, start, "directive %q at offset %d has no Go layout equivalent", directive, start)
+			}
+			if lenient {
+				// Part of the literal text up to the next directive.
+				continue
+			}
+			return "", unsupported(directive, start)
+		}
+		add(last, format[last:start], format[last:start])
+		add(start, directive, subst)
+		last = end
+	}
+	add(last, format[last:], format[last:])
+
+	native := b.String()
+	if err := checkNative(format, native, pieces); err != nil {
+		return "", err
+	}
+	return native, nil
+}
 
-	var errs []error
-	replaceFunc := func(directive string) string {
-		if subst, ok := ctimeSubstitutes[directive]; ok {
-			return subst
-		} else {
-			errs = append(errs, errors.New("unsupported ctimefmt.ToNative() directive: "+directive))
+// fracLayout reports whether native is made of the digits of a Go
+// fractional-seconds layout element, e.g. 000 or 999999.
+func fracLayout(native string) bool {
+	return native != "" && (strings.Trim(native, "0") == "" || strings.Trim(native, "9") == "")
+}
+
+// checkNative returns an error if the Go layout native, made of pieces,
+// has layout elements other than those of the directive substitutes, e.g.
+// because literal digits of the format read as an element. Pieces whose
+// text is the same in the format and the layout are literal text.
+func checkNative(format, native string, pieces []nativePiece) error {
+	type element struct {
+		offset int
+		std    string
+	}
+	elements := func(layout string, offset int) []element {
+		var elems []element
+		for {
+			prefix, std, suffix := nextStdChunk(layout)
+			if std == "" {
+				return elems
+			}
+			elems = append(elems, element{offset + len(prefix), std})
+			offset += len(layout) - len(suffix)
+			layout = suffix
+		}
+	}
+	var expected []element
+	for _, p := range pieces {
+		if p.source == p.text {
+			continue
+		}
+		if fracLayout(p.text) && p.native > 0 {
+			// Go's fractional seconds include the separator.
+			if sep := native[p.native-1]; sep == '.' || sep == ',' {
+				expected = append(expected, element{p.native - 1, native[p.native-1 : p.native+len(p.text)]})
+				continue
+			}
 		}
-		return ""
+		expected = append(expected, elements(p.text, p.native)...)
 	}
+	got := elements(native, 0)
+	for i, e := range got {
+		if i < len(expected) && e == expected[i] {
+			continue
+		}
+		// Map the element back to the format text it was made of.
+		start, end := 0, len(format)
+		for _, p := range pieces {
+			literal := p.source == p.text
+			if p.native <= e.offset && e.offset < p.native+len(p.text) {
+				start = p.format
+				if literal {
+					start += e.offset - p.native
+				}
+			}
+			if n := e.offset + len(e.std); p.native < n && n <= p.native+len(p.text) {
+				end = p.format + len(p.source)
+				if literal {
+					end = p.format + n - p.native
+				}
+			}
+		}
+		return directiveError(format[start:end], start, "%q at offset %d would read as Go layout element %q", format[start:end], start, e.std)
+	}
+	return nil
+}
 
-	replaced := ctimeRegexp.ReplaceAllStringFunc(format, replaceFunc)
-	if len(errs) != 0 {
-		return "", fmt.Errorf("convert to go time format: %v", errs)
+// modifiers maps the POSIX E and O modifiers to the conversions they
+// apply to.
+var modifiers = map[byte]string{
+	'E': "cCxXyY",
+	'O': "deHImMSuUVwWy",
+}
+
+// stripModifier removes an E or O modifier, e.g. from %Ey or %Od, which
+// select alternative representations of the locale. Those are the same
+// as the plain directives here.
+func stripModifier(directive string) string {
+	n := len(directive)
+	if n < 3 {
+		return directive
+	}
+	if convs, ok := modifiers[directive[n-2]]; ok && strings.IndexByte(convs, directive[n-1]) >= 0 {
+		return directive[:n-2] + directive[n-1:]
+	}
+	return directive
+}
+
+// unsupported returns the error for an unknown directive at offset.
+func unsupported(directive string, offset int) error {
+	if strings.ContainsAny(directive, "0123456789") {
+		return directiveError(directive, offset, "unsupported width in directive %q at offset %d", directive, offset)
 	}
+	return directiveError(directive, offset, "unsupported directive %q at offset %d", directive, offset)
+}
+
+// DirectiveError is returned by ToNative, Validate, Compile and the other
+// functions converting a format for a directive they do not support, or
+// literal text ToNative cannot convert. Tools can use the offending text
+// and its position, e.g. to highlight it.
+type DirectiveError struct {
+	Directive string // the directive or literal text, e.g. "%v"
+	Offset    int    // byte offset of Directive in the format
+	msg       string
+}
+
+func directiveError(directive string, offset int, format string, a ...interface{}) *DirectiveError {
+	return &DirectiveError{Directive: directive, Offset: offset, msg: fmt.Sprintf(format, a...)}
+}
 
-	return replaced, nil
+func (e *DirectiveError) Error() string {
+	return e.msg
 }
@@ -2,6 +2,8 @@ package ctimefmt
 
 import "time"
 import "testing"
+import "errors"
+import "strings"
 
 var format1 string = "%Y-%m-%d %H:%M:%S.%f"
 var format2 string = "%Y-%m-%d %l:%M:%S.%L %P, %a"
@@ -19,12 +21,13 @@ func TestFormat(t *testing.T) {
 		t.Errorf("Given: %v, expected: %v", s, value1)
 	}
 
+	// %l is space-padded, which Parse accepts with or without the padding.
 	s, err = Format(format2, dt1)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if s != value2 {
-		t.Errorf("Given: %v, expected: %v", s, value2)
+	if expected := "2019-01-02  3:04:05.666 pm, Wed"; s != expected {
+		t.Errorf("Given: %v, expected: %v", s, expected)
 	}
 }
 
@@ -43,3 +46,1145 @@ func TestParse(t *testing.T) {
 		t.Errorf("Given: %v, expected: %v", dt_, dt2)
 	}
 }
+
+func TestToNativeErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		err    string
+	}{
+		{"%Y-%m-%d %H:%M:%v", `unsupported directive "%v" at offset 15`},
+		{"%Y %!", `unsupported directive "%!" at offset 3`},
+		{"%Y-%m-01", `"01" at offset 6 would read as Go layout element "01"`},
+		{"Jan %d", `"Jan" at offset 0 would read as Go layout element "Jan"`},
+		{"%buary", `"%buary" at offset 0 would read as Go layout element "January"`},
+		{"%S.999", `".999" at offset 2 would read as Go layout element ".999"`},
+	}
+	for _, test := range tests {
+		_, err := ToNative(test.format)
+		if err == nil {
+			t.Errorf("Given: nil error, expected: %v", test.err)
+		} else if err.Error() != test.err {
+			t.Errorf("Given: %v, expected: %v", err, test.err)
+		}
+	}
+}
+
+func TestAppendNative(t *testing.T) {
+	b := []byte("layout: ")
+	b, err := AppendNative(b, "%Y-%m-%d %H:%M:%S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "layout: 2006-01-02 15:04:05"; string(b) != expected {
+		t.Errorf("Given: %v, expected: %v", string(b), expected)
+	}
+
+	b, err = AppendNative(b, "%j")
+	if err == nil {
+		t.Error("AppendNative: expected an error for a directive without a Go layout equivalent")
+	}
+	if expected := "layout: 2006-01-02 15:04:05"; string(b) != expected {
+		t.Errorf("Given: %v, expected: %v", string(b), expected)
+	}
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = AppendNative(buf[:0], format1)
+	})
+	if allocs != 0 {
+		t.Errorf("Given: %v allocations, expected: 0", allocs)
+	}
+}
+
+func TestFormatParseErrors(t *testing.T) {
+	if _, err := Format("%v", dt1); err == nil {
+		t.Error("Format: expected an error for an unsupported directive")
+	}
+	if _, err := Parse("%v", "2019"); err == nil {
+		t.Error("Parse: expected an error for an unsupported directive")
+	}
+}
+
+func TestLowercaseMeridiem(t *testing.T) {
+	noon := time.Date(2019, 1, 2, 12, 0, 0, 0, time.UTC)
+	s, err := Format("%I:%M %P", noon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "12:00 pm" {
+		t.Errorf("Given: %v, expected: %v", s, "12:00 pm")
+	}
+
+	tests := []struct {
+		value string
+		hour  int
+	}{
+		{"12:00 am", 0},
+		{"09:30 am", 9},
+		{"12:00 pm", 12},
+		{"09:30 pm", 21},
+	}
+	for _, test := range tests {
+		dt, err := Parse("%I:%M %P", test.value)
+		if err != nil {
+			t.Error(err)
+		} else if dt.Hour() != test.hour {
+			t.Errorf("Given: %v, expected: %v", dt.Hour(), test.hour)
+		}
+	}
+
+	if _, err := Parse("%I:%M %P", "09:30 PM"); err == nil {
+		t.Error("Parse: expected an error for upper-case PM with %P")
+	}
+}
+
+func TestParseInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	dt, err := ParseInLocation("%Y-%m-%d %H:%M:%S", "2021-07-04 12:00:00", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2021, 7, 4, 12, 0, 0, 0, loc); !dt.Equal(expected) || dt.Location() != loc {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+	if name, offset := dt.Zone(); name != "EDT" || offset != -4*3600 {
+		t.Errorf("Given: %v %v, expected: EDT %v", name, offset, -4*3600)
+	}
+
+	// A zone abbreviation of the location resolves to it.
+	dt, err = ParseInLocation("%Y-%m-%d %H:%M:%S %Z", "2021-01-04 12:00:00 EST", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2021, 1, 4, 17, 0, 0, 0, time.UTC); !dt.Equal(expected) || dt.Location() != loc {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	// An explicit offset takes precedence over the location.
+	dt, err = ParseInLocation("%Y-%m-%d %H:%M:%S %z", "2021-07-04 12:00:00 +0200", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2021, 7, 4, 10, 0, 0, 0, time.UTC); !dt.Equal(expected) {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	expected, _ := time.ParseInLocation("2006-01-02 15:04:05 MST", "2021-03-14 01:30:00 EST", loc)
+	dt, err = ParseInLocation("%Y-%m-%d %H:%M:%S %Z", "2021-03-14 01:30:00 EST", loc)
+	if err != nil {
+		t.Fatal(err)
+	} else if !dt.Equal(expected) || dt.String() != expected.String() {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+}
+
+func TestFormatInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Daylight saving time started on 2021-03-14 at 07:00 UTC.
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2021, 3, 14, 6, 59, 59, 0, time.UTC), "2021-03-14 01:59:59 EST -0500"},
+		{time.Date(2021, 3, 14, 7, 0, 0, 0, time.UTC), "2021-03-14 03:00:00 EDT -0400"},
+	}
+	for _, test := range tests {
+		s, err := FormatInLocation("%Y-%m-%d %H:%M:%S %Z %z", test.dt, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+}
+
+func TestDirectiveError(t *testing.T) {
+	tests := []struct {
+		format    string
+		directive string
+		offset    int
+	}{
+		{"%Y-%m-%d %H:%M:%v", "%v", 15},
+		{"%H:%M:%S.%0f", "%0f", 9},
+		{"%Y-%j", "%j", 3},
+		{"%Y-%m-01", "01", 6},
+	}
+	for _, test := range tests {
+		_, err := ToNative(test.format)
+		e, ok := err.(*DirectiveError)
+		if !ok {
+			t.Errorf("ToNative(%q): given: %#v, expected a *DirectiveError", test.format, err)
+			continue
+		}
+		if e.Directive != test.directive || e.Offset != test.offset {
+			t.Errorf("Given: %q at %v, expected: %q at %v", e.Directive, e.Offset, test.directive, test.offset)
+		}
+	}
+
+	err := Validate("%Y %m %v")
+	if e, ok := err.(*DirectiveError); !ok {
+		t.Errorf("Validate: given: %#v, expected a *DirectiveError", err)
+	} else if e.Directive != "%v" || e.Offset != 6 || e.Error() != `unsupported directive "%v" at offset 6` {
+		t.Errorf("Given: %q at %v (%v)", e.Directive, e.Offset, e)
+	}
+}
+
+func TestFormatUTC(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	s, err := FormatUTC("%Y-%m-%d %H:%M:%S %Z %z", dt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "2021-03-05 04:30:00 UTC +0000"; s != expected {
+		t.Errorf("Given: %v, expected: %v", s, expected)
+	}
+
+	if _, err := FormatUTC("%Y-%v", dt); err == nil {
+		t.Error("FormatUTC: expected an error for an unsupported directive")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{"", "%Y-%m-%d", format1, format2, "%j %k %s %3f", "%% %c", "%Y/%m/10"}
+	for _, format := range valid {
+		if err := Validate(format); err != nil {
+			t.Errorf("Validate(%q): %v", format, err)
+		}
+	}
+
+	invalid := []struct {
+		format, err string
+	}{
+		{"%Y-%m-%d %v", `unsupported directive "%v" at offset 9`},
+		{"%H:%M:%S.%0f", `unsupported width in directive "%0f" at offset 9`},
+	}
+	for _, test := range invalid {
+		if err := Validate(test.format); err == nil {
+			t.Errorf("Validate(%q): expected an error", test.format)
+		} else if err.Error() != test.err {
+			t.Errorf("Given: %v, expected: %v", err, test.err)
+		}
+	}
+}
+
+func TestAppendFormat(t *testing.T) {
+	buf := []byte("time: ")
+	buf, err := AppendFormat(buf, format1, dt1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "time: " + value1; string(buf) != expected {
+		t.Errorf("Given: %v, expected: %v", string(buf), expected)
+	}
+
+	buf, err = AppendFormat(buf[:0], "%v", dt1)
+	if err == nil {
+		t.Error("AppendFormat: expected an error for an unsupported directive")
+	}
+	if len(buf) != 0 {
+		t.Errorf("Given: %q, expected an unchanged buffer", buf)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2019-01-02 15:04:05.5"},
+		{"%Y-%m-%d %H:%M:%S", "2019-01-02  15:04:05"},
+		{"%H:%M:%S %Z", "15:04:05,123 UTC"},
+	}
+	for _, test := range tests {
+		if _, err := Parse(test.format, test.value); err != nil {
+			t.Errorf("Parse(%q): %v", test.value, err)
+		}
+		if _, err := ParseStrict(test.format, test.value); err == nil {
+			t.Errorf("ParseStrict(%q): expected an error", test.value)
+		}
+	}
+
+	for _, value := range []string{"2019-01-02 15:04:05x", "2019-01-02 15:04:05 "} {
+		if _, err := ParseStrict("%Y-%m-%d %H:%M:%S", value); err == nil {
+			t.Errorf("ParseStrict(%q): expected an error", value)
+		}
+	}
+
+	dt, err := ParseStrict(format1, value1)
+	if err != nil {
+		t.Error(err)
+	} else if dt != dt1 {
+		t.Errorf("Given: %v, expected: %v", dt, dt1)
+	}
+}
+
+func TestLiteralDigits(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%Y/%m/%d-01", "2021/03/04-01"},
+		{"Q1 %Y", "Q1 2021"},
+		{"%H:%M 100%% 2nd", "05:06 100% 2nd"},
+		{"%Y0%m", "2021003"},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+		if _, err := Parse(test.format, s); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Digits which cannot read as Go layout elements pass through ToNative.
+	native, err := ToNative("Year 7 %Y 89")
+	if err != nil {
+		t.Error(err)
+	} else if expected := "Year 7 2006 89"; native != expected {
+		t.Errorf("Given: %v, expected: %v", native, expected)
+	}
+	if _, err := ToNative("%Y/%m/%d-01"); err == nil {
+		t.Error("ToNative: expected an error for literal digits reading as a Go layout element")
+	}
+}
+
+// Literal letters are Go layout text unless they read as an element such as
+// Z07 or MST, which Go layouts cannot quote.
+func TestLiteralLetters(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		format   string
+		native   string
+		expected string
+	}{
+		{"%Y-%m-%dT%H:%M:%SZ", "2006-01-02T15:04:05Z", "2021-03-04T05:06:07Z"},
+		{"%Y-%m-%dT%H:%M:%ST", "2006-01-02T15:04:05T", "2021-03-04T05:06:07T"},
+		{"%Y%m%dT%H%M%SZ%z", "20060102T150405Z-0700", "20210304T050607Z+0000"},
+		{"%d%b%YT%H:%M:%STZ", "02Jan2006T15:04:05TZ", "04Mar2021T05:06:07TZ"},
+	}
+	for _, test := range tests {
+		native, err := ToNative(test.format)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if native != test.native {
+			t.Errorf("Given: %v, expected: %v", native, test.native)
+		}
+		if s := dt.Format(native); s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+		if s, err := Format(test.format, dt); err != nil || s != test.expected {
+			t.Errorf("Given: %v, %v, expected: %v", s, err, test.expected)
+		}
+		if parsed, err := time.Parse(native, test.expected); err != nil || !parsed.Equal(dt) {
+			t.Errorf("Given: %v, %v, expected: %v", parsed, err, dt)
+		}
+	}
+
+	for _, format := range []string{"%H:%M:%SZ07", "%dTMST", "%H%MPM"} {
+		if _, err := ToNative(format); err == nil {
+			t.Errorf("%q: expected an error for letters reading as a Go layout element", format)
+		}
+	}
+}
+
+func TestLiteralWords(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 17, 6, 0, 0, time.UTC)
+	tests := []struct {
+		format   string
+		native   string
+		expected string
+	}{
+		{"%B %d, %Y at %I:%M %p", "January 02, 2006 at 03:04 PM", "March 04, 2021 at 05:06 PM"},
+		{"%B %-d, %Y at %-I:%M %p", "January 2, 2006 at 3:04 PM", "March 4, 2021 at 5:06 PM"},
+		{"on the %dth of %B %Y, at %H:%M", "on the 02th of January 2006, at 15:04", "on the 04th of March 2021, at 17:06"},
+	}
+	for _, test := range tests {
+		native, err := ToNative(test.format)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if native != test.native {
+			t.Errorf("Given: %v, expected: %v", native, test.native)
+		}
+		if s, err := Format(test.format, dt); err != nil || s != test.expected {
+			t.Errorf("Given: %v, %v, expected: %v", s, err, test.expected)
+		}
+		if parsed, err := Parse(test.format, test.expected); err != nil || !parsed.Equal(dt) {
+			t.Errorf("Given: %v, %v, expected: %v", parsed, err, dt)
+		}
+		if parsed, err := time.Parse(native, test.expected); err != nil || !parsed.Equal(dt) {
+			t.Errorf("Given: %v, %v, expected: %v", parsed, err, dt)
+		}
+	}
+
+	// Parse accepts the day and hour without their zero padding.
+	format, value := "%B %d, %Y at %I:%M %p", "March 4, 2021 at 5:06 PM"
+	if parsed, err := Parse(format, value); err != nil || !parsed.Equal(dt) {
+		t.Errorf("Given: %v, %v, expected: %v", parsed, err, dt)
+	}
+	if parsed, err := CompileLenient(format).Parse("march 4, 2021 AT 5:06 pm"); err != nil || !parsed.Equal(dt) {
+		t.Errorf("Given: %v, %v, expected: %v", parsed, err, dt)
+	}
+	if _, err := ParseStrict(format, value); err == nil {
+		t.Errorf("ParseStrict(%q): expected an error", value)
+	}
+
+	// Literal words match case-sensitively, except in lenient layouts.
+	format, value = "%B %-d, %Y at %-I:%M %p", "MARCH 4, 2021 AT 5:06 PM"
+	if _, err := Parse(format, value); err == nil {
+		t.Errorf("%q: expected an error for literal text of another case", value)
+	}
+	l := CompileLenient(format)
+	if parsed, err := l.Parse(value); err != nil || !parsed.Equal(dt) {
+		t.Errorf("Given: %v, %v, expected: %v", parsed, err, dt)
+	}
+	if _, err := l.ParseStrict(value); err == nil {
+		t.Errorf("%q: expected an error for literal text of another case", value)
+	}
+	if _, err := l.Parse("March 4, 2021 on 5:06 PM"); err == nil {
+		t.Error("expected an error for other literal text")
+	}
+}
+
+func TestPercent(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+		native   string
+	}{
+		{"%%", "%", "%"},
+		{"%%%", "%%", "%%"},
+		{"%%Y", "%Y", "%Y"},
+		{"%%%Y", "%2021", "%2006"},
+		{"%%%%Y", "%%Y", "%%Y"},
+		{"%Y%", "2021%", "2006%"},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Format(%q): given: %v, expected: %v", test.format, s, test.expected)
+		}
+		native, err := ToNative(test.format)
+		if err != nil {
+			t.Error(err)
+		} else if native != test.native {
+			t.Errorf("ToNative(%q): given: %v, expected: %v", test.format, native, test.native)
+		}
+		if _, err := Parse(test.format, s); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestParseFlexibleSpace(t *testing.T) {
+	expected := time.Date(0, 1, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		format string
+		value  string
+	}{
+		{"%b %e %H:%M:%S", "Jan  4 05:06:07"},
+		{"%b %e %H:%M:%S", "Jan 4 05:06:07"},
+		{"%b %d %H:%M:%S", "Jan  04 05:06:07"},
+		{"%b  %e %H:%M:%S", "Jan 4 05:06:07"},
+		{"%b %e   %H:%M:%S", "Jan  4 05:06:07"},
+		{Syslog, "Jan  4 05:06:07"},
+	}
+	for _, test := range tests {
+		dt, err := Parse(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+		} else if dt != expected {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+	}
+
+	// A space in the format needs at least one in the value.
+	if _, err := Parse("%b %e %H:%M:%S", "Jan4 05:06:07"); err == nil {
+		t.Error("Parse: expected an error for a missing space")
+	}
+	if _, err := ParseStrict("%b %d %H:%M:%S", "Jan  04 05:06:07"); err == nil {
+		t.Error("ParseStrict: expected an error for a run of spaces")
+	}
+}
+
+func TestParseNameCase(t *testing.T) {
+	expected := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"01 jan 2021", "01 JAN 2021", "01 Jan 2021", "01 jAn 2021"} {
+		dt, err := Parse("%d %b %Y", value)
+		if err != nil {
+			t.Error(err)
+		} else if dt != expected {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+	}
+	tests := []struct {
+		format string
+		value  string
+	}{
+		{"%A, %B %d %Y", "friday, january 01 2021"},
+		{"%A, %B %d %Y", "FRIDAY, JANUARY 01 2021"},
+		{"%a, %B %d %Y", "FRI, JANUARY 01 2021"},
+	}
+	for _, test := range tests {
+		dt, err := Parse(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+		} else if dt != expected {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+	}
+}
+
+func TestDateDefault(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), "Thu Mar  4 05:06:07 UTC 2021"},
+		{time.Date(2021, 12, 25, 17, 6, 7, 0, time.FixedZone("EST", -5*3600)), "Sat Dec 25 17:06:07 EST 2021"},
+	}
+	for _, test := range tests {
+		s, err := Format("%+", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+		if expected := test.dt.Format(time.UnixDate); s != expected {
+			t.Errorf("Given: %q, expected: %q", s, expected)
+		}
+	}
+}
+
+func TestLenient(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"100% %H", "100% 15"},
+		{"100% done %H:%M", "100% done 15:04"},
+		{"%!%Y %v", "%!2021 %v"},
+		{"%%v %-v %H", "%v %-v 15"},
+	}
+	for _, test := range tests {
+		if _, err := Compile(test.format); err == nil {
+			t.Errorf("Compile(%q): expected an error", test.format)
+		}
+		l := CompileLenient(test.format)
+		if s := l.Format(dt); s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+		if _, err := l.Parse(test.expected); err != nil {
+			t.Error(err)
+		}
+	}
+
+	native, err := ToNativeLenient("at 99% %H:%M %!")
+	if err != nil {
+		t.Error(err)
+	} else if expected := "at 99% 15:04 %!"; native != expected {
+		t.Errorf("Given: %v, expected: %v", native, expected)
+	}
+	if _, err := ToNativeLenient("100% %H"); err == nil {
+		t.Error("ToNativeLenient: expected an error for literal digits reading as a Go layout element")
+	}
+	if _, err := ToNativeLenient("%j"); err == nil {
+		t.Error("ToNativeLenient: expected an error for a directive without Go layout equivalent")
+	}
+}
+
+// A multi-byte character after a percent sign is a single unsupported
+// directive, never split into bytes.
+func TestMultiByteDirective(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC)
+	// %H converts to 15 as well, so the lenient layouts and texts match.
+	tests := []struct {
+		format    string
+		directive string
+		offset    int
+		lenient   string
+	}{
+		{"%€", "%€", 0, "%€"},
+		{"price %€ %H", "%€", 6, "price %€ 15"},
+		{"%日付 %H", "%日", 0, "%日付 15"},
+		{"%-€%H", "%-€", 0, "%-€15"},
+		{"%H%😀", "%😀", 2, "15%😀"},
+		{"%\xff %H", "%\xff", 0, "%\xff 15"},
+	}
+	for _, test := range tests {
+		_, err := ToNative(test.format)
+		derr, ok := err.(*DirectiveError)
+		if !ok {
+			t.Errorf("%q: given: %v, expected: a DirectiveError", test.format, err)
+		} else if derr.Directive != test.directive || derr.Offset != test.offset {
+			t.Errorf("Given: %q at %v, expected: %q at %v", derr.Directive, derr.Offset, test.directive, test.offset)
+		}
+
+		native, err := ToNativeLenient(test.format)
+		if err != nil {
+			t.Error(err)
+		} else if native != test.lenient {
+			t.Errorf("Given: %q, expected: %q", native, test.lenient)
+		}
+		if s := CompileLenient(test.format).Format(dt); s != test.lenient {
+			t.Errorf("Given: %q, expected: %q", s, test.lenient)
+		}
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	formats := []string{RFC3339, "%d/%b/%Y:%H:%M:%S %z", "%s"}
+	tests := []struct {
+		value    string
+		index    int
+		expected time.Time
+	}{
+		{"2021-03-04T05:06:07+00:00", 0, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{"04/Mar/2021:05:06:07 +0000", 1, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{"1614834367", 2, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, i, err := ParseAny(formats, test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if i != test.index {
+			t.Errorf("ParseAny(%q): given index: %v, expected: %v", test.value, i, test.index)
+		}
+		if !dt.Equal(test.expected) {
+			t.Errorf("Given: %v, expected: %v", dt, test.expected)
+		}
+	}
+
+	if _, i, err := ParseAny(formats, "04 Mar 2021"); err == nil || i != -1 {
+		t.Errorf("ParseAny: expected an error for an unknown format, given index: %v", i)
+	}
+	if _, _, err := ParseAny([]string{"%Y", "%v"}, "x"); err == nil {
+		t.Error("ParseAny: expected an error for an unsupported directive")
+	}
+	if _, _, err := ParseAny(nil, "2021"); err == nil {
+		t.Error("ParseAny: expected an error without formats")
+	}
+}
+
+func TestWouldTruncate(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		expected bool
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2021-03-04 05:06:07.123", true},
+		{"%Y-%m-%d %H:%M:%S", "2021-03-04 05:06:07,5", true},
+		{"%Y-%m-%d %H:%M:%S", "2021-03-04 05:06:07", false},
+		{"%Y-%m-%d %H:%M:%S", "2021-03-04 05:06:07.000", false},
+		{"%Y-%m-%d %H:%M:%S.%L", "2021-03-04 05:06:07.123", false},
+		{"%Y-%m-%d %H:%M:%S.%f", "2021-03-04 05:06:07.123456", false},
+		{"%H:%M:%S %z", "05:06:07.5 +0100", true},
+		{"%Q", "1614834367891", false},
+	}
+	for _, test := range tests {
+		truncated, err := WouldTruncate(test.format, test.value)
+		if err != nil {
+			t.Errorf("WouldTruncate(%q, %q): %v", test.format, test.value, err)
+		} else if truncated != test.expected {
+			t.Errorf("WouldTruncate(%q, %q): given: %v, expected: %v", test.format, test.value, truncated, test.expected)
+		}
+	}
+
+	if _, err := WouldTruncate("%Y-%m-%d", "2021-03-04 05:06"); err == nil {
+		t.Error("WouldTruncate: expected an error for a value which does not parse")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%D", "%m/%d/%Y"},
+		{"%x", "%m/%d/%Y"},
+		{"%F", "%Y-%m-%d"},
+		{"%T", "%H:%M:%S"},
+		{"%X", "%H:%M:%S"},
+		{"%R", "%H:%M"},
+		{"%r", "%I:%M:%S %P"},
+		{"%c", "%a %b %d %H:%M:%S %Y"},
+		{"%+", "%a %b %e %H:%M:%S %Z %Y"},
+		{"%o", "%_m"},
+		{"%Ec %EY %Od", "%a %b %d %H:%M:%S %Y %Y %d"},
+		{"at %F %T.%L%z", "at %Y-%m-%d %H:%M:%S.%L%z"},
+		{"%-d %^B %% %n", "%-d %^B %% %n"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		s, err := Canonicalize(test.format)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("Canonicalize(%q): given: %v, expected: %v", test.format, s, test.expected)
+		}
+	}
+
+	a, _ := Canonicalize("%F %T")
+	b, _ := Canonicalize("%Y-%m-%d %OH:%M:%S")
+	if a != b {
+		t.Errorf("Given: %v, expected: %v", a, b)
+	}
+
+	if _, err := Canonicalize("%F %v"); err == nil || err.Error() != `unsupported directive "%v" at offset 3` {
+		t.Errorf("Canonicalize: expected an error for an unsupported directive, given: %v", err)
+	}
+}
+
+func TestFractionalDigits(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected int
+	}{
+		{"", 0},
+		{"%Y-%m-%d %H:%M:%S", 0},
+		{"%c %s", 0},
+		{"%H:%M:%S.%L", 3},
+		{"%H:%M:%S,%L", 3},
+		{format1, 6},
+		{"%H:%M:%S.%N", 9},
+		{"%S.%1f", 1},
+		{"%S.%4f", 4},
+		{ISO8601Millis, 3},
+		{"%L %N", 9},
+	}
+	for _, test := range tests {
+		digits, err := FractionalDigits(test.format)
+		if err != nil {
+			t.Error(err)
+		} else if digits != test.expected {
+			t.Errorf("FractionalDigits(%q): given: %v, expected: %v", test.format, digits, test.expected)
+		}
+	}
+
+	if _, err := FractionalDigits("%S.%v"); err == nil {
+		t.Error("FractionalDigits: expected an error for an unsupported directive")
+	}
+}
+
+func TestMaxFormattedLen(t *testing.T) {
+	times := []time.Time{
+		dt1,
+		time.Date(2021, 9, 22, 23, 59, 59, 999999999, time.FixedZone("", -(7*3600+30*60+15))),
+		time.Date(9999, 12, 31, 12, 0, 0, 0, time.FixedZone("", 14*3600)),
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.FixedZone("", -(23*3600+59*60))),
+	}
+	formats := []string{format1, "%A, %B %:d %Y", "%c", "%D %r", "%s.%N", "%9s %EZ", "%^a %-d %_H %Oz %-i", "%Y-%m-%dT%H:%M:%S,%3f%#:z"}
+	for token := range ctimeDirectives {
+		if token != "%Z" && token != "%:Z" && token != "%+" {
+			formats = append(formats, token)
+		}
+	}
+	for _, format := range formats {
+		max, err := MaxFormattedLen(format)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		for _, dt := range times {
+			if s, err := Format(format, dt); err != nil {
+				t.Fatal(err)
+			} else if len(s) > max {
+				t.Errorf("%q: given: %v bytes for %q, expected at most: %v", format, len(s), s, max)
+			}
+		}
+	}
+
+	if max, err := MaxFormattedLen("%A %B %d"); err != nil || max != 22 {
+		t.Errorf("Given: %v, %v, expected: %v", max, err, 22)
+	}
+	// The ^ flag keeps the width of numeric directives.
+	for format, expected := range map[string]int{"%^d": 2, "%^H:%^M": 5, "%^Y-%^j": 8, "%^S.%L": 6} {
+		if max, err := MaxFormattedLen(format); err != nil || max != expected {
+			t.Errorf("%q: given: %v, %v, expected: %v", format, max, err, expected)
+		}
+	}
+	for _, format := range []string{"%H %Z", "%:Z", "%+", "%v"} {
+		if _, err := MaxFormattedLen(format); err == nil {
+			t.Errorf("%q: expected an error", format)
+		}
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		formatA, formatB string
+		expected         bool
+	}{
+		{"%Y-%m-%d %H:%M:%S", "%F %T", true},
+		{"%Y-%m-%d %H:%M:%S", "%Y-%m-%d %H:%M:%S", true},
+		{"%Y-%m-%d %H:%M:%S.%L", "%Y-%m-%d %H:%M:%S.%f", true},
+		{"%Y-%m-%d %H:%M:%S.%f", "%Y-%m-%d %H:%M:%S.%L", false},
+		{"%d/%m/%Y", "%-d/%-m/%Y", true},
+		{"%-d/%-m/%Y", "%d/%m/%Y", false},
+		{"%Y-%m-%dT%H:%M:%S%z", "%Y-%m-%dT%H:%M:%S%Ez", true},
+		{"%Y-%m-%d", "%Y-%d-%m", false},
+		{"%H:%M:%S %z", "%H:%M:%S", false},
+		{"%d %b %Y", "%d %B %Y", false},
+		{"%s", "%Y-%m-%d", false},
+		{"%Y %H:%M", "%:Y %H:%M", true},
+	}
+	for _, test := range tests {
+		ok, err := Compatible(test.formatA, test.formatB)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if ok != test.expected {
+			t.Errorf("Compatible(%q, %q): given: %v, expected: %v", test.formatA, test.formatB, ok, test.expected)
+		}
+	}
+
+	if _, err := Compatible("%Y", "%v"); err == nil {
+		t.Error("Compatible: expected an error for an unsupported directive")
+	}
+}
+
+func TestParseTrimmed(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		expected time.Time
+	}{
+		{"%Y-%m-%d", "  2021-03-04  ", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"%Y-%m-%d", "\t2021-03-04\r\n", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"%Y-%m-%d %H:%M", " 2021-03-04 05:06 ", time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)},
+		{"%e %b", "  4 Mar", time.Date(0, 3, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := ParseTrimmed(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if dt != test.expected {
+			t.Errorf("ParseTrimmed(%q): given: %v, expected: %v", test.value, dt, test.expected)
+		}
+	}
+
+	// White space within the value still has to match the format.
+	if _, err := ParseTrimmed("%Y-%m-%d %H:%M", " 2021-03-0405:06 "); err == nil {
+		t.Error("ParseTrimmed: expected an error for missing interior white space")
+	}
+	if _, err := Parse("%Y-%m-%d", "  2021-03-04  "); err == nil {
+		t.Error("Parse: expected an error for surrounding white space")
+	}
+}
+
+func TestParseCombined(t *testing.T) {
+	tz := time.FixedZone("", 5*3600+1800)
+	tests := []struct {
+		dateFormat, dateValue string
+		timeFormat, timeValue string
+		expected              time.Time
+	}{
+		{"%Y-%m-%d", "2021-03-04", "%H:%M:%S", "05:06:07", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{"%d/%b/%Y", "04/Mar/2021", "%H:%M:%S.%L", "05:06:07.123", time.Date(2021, 3, 4, 5, 6, 7, 123000000, time.UTC)},
+		{"%Y-%m-%d", "2021-03-04", "%H:%M:%S%:z", "05:06:07+05:30", time.Date(2021, 3, 4, 5, 6, 7, 0, tz)},
+		{"%Y-%m-%d %z", "2021-03-04 +0530", "%H:%M:%S", "05:06:07", time.Date(2021, 3, 4, 5, 6, 7, 0, tz)},
+		{"%Y-%m-%d %z", "2021-03-04 -0700", "%H:%M:%S%:z", "05:06:07+05:30", time.Date(2021, 3, 4, 5, 6, 7, 0, tz)},
+		// The time of day of the date value is dropped.
+		{"%Y-%m-%d %H", "2021-03-04 23", "%H:%M", "05:06", time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := ParseCombined(test.dateFormat, test.dateValue, test.timeFormat, test.timeValue)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !dt.Equal(test.expected) {
+			t.Errorf("Given: %v, expected: %v", dt, test.expected)
+		}
+		_, offset := dt.Zone()
+		if _, expected := test.expected.Zone(); offset != expected {
+			t.Errorf("Given offset: %v, expected: %v", offset, expected)
+		}
+	}
+
+	if _, err := ParseCombined("%Y-%m-%d", "2021-02-30", "%H:%M", "05:06"); err == nil {
+		t.Error("ParseCombined: expected an error for an invalid date")
+	}
+	if _, err := ParseCombined("%Y-%m-%d", "2021-03-04", "%H:%M", "25:06"); err == nil {
+		t.Error("ParseCombined: expected an error for an invalid time")
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 15, 4, 5, 123456789, time.FixedZone("", 2*3600))
+	tests := []struct {
+		format   string
+		expected time.Time
+	}{
+		{"%Y-%m-%dT%H:%M:%S.%N%:z", dt},
+		{"%Y-%m-%d %H:%M:%S.%N", dt},
+		{"%Y-%m-%d %H:%M:%S", time.Date(2021, 3, 4, 15, 4, 5, 0, dt.Location())},
+		{"%m-%d %H:%M", time.Date(0, 3, 4, 15, 4, 0, 0, dt.Location())},
+		{"%Y-%m-%d %I:%M %p", time.Date(2021, 3, 4, 15, 4, 0, 0, dt.Location())},
+	}
+	for _, test := range tests {
+		rt, err := Roundtrip(test.format, dt)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !rt.Equal(test.expected) {
+			t.Errorf("Given: %v, expected: %v", rt, test.expected)
+		}
+		if lossless := test.expected.Equal(dt); rt.Equal(dt) != lossless {
+			t.Errorf("Roundtrip(%q): expected the round trip to be lossless: %v", test.format, lossless)
+		}
+	}
+
+	if _, err := Roundtrip("%Y-%v", dt); err == nil {
+		t.Error("Roundtrip: expected an error for an unsupported directive")
+	}
+}
+
+func TestParseChecked(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		valid  bool
+	}{
+		{"%A %Y-%m-%d", "Tuesday 2021-03-02", true},
+		{"%A %Y-%m-%d", "Monday 2021-03-02", false},
+		{"%a, %d %b %Y", "tue, 02 Mar 2021", true},
+		{"%a, %d %b %Y", "Wed, 02 Mar 2021", false},
+		{"%Y-%m-%d %u", "2021-03-07 7", true},
+		{"%Y-%m-%d %u", "2021-03-07 1", false},
+		{"%Y-%m-%d %w", "2021-03-07 0", true},
+		{"%Y-%m-%d %w", "2021-03-07 6", false},
+		{"%Y-%j %A", "2021-061 Tuesday", true},
+		{"%Y-%j %A", "2021-061 Friday", false},
+		{"%s %A", "1614729600 Wednesday", true},
+		{"%s %A", "1614729600 Tuesday", false},
+		// The weekday of a time in a zone is that of its local date.
+		{"%A %Y-%m-%d %H:%M %z", "Tuesday 2021-03-02 23:30 -0500", true},
+		// Without a day there is nothing to check.
+		{"%A %H:%M", "Monday 15:04", true},
+		// The weekday name and number must agree.
+		{"%A (%u) %Y-%m-%d", "Sunday (7) 2021-03-07", true},
+		{"%A (%u) %Y-%m-%d", "Sunday (1) 2021-03-07", false},
+		{"%Y-%m-%d %u %a", "2021-03-07 7 Mon", false},
+		{"%a %w %Y-%m-%d", "Sun 0 2021-03-07", true},
+		{"%A %u", "Monday 1", true},
+		{"%A %u", "Monday 2", false},
+		{"%G-W%V-%u %A", "2021-W09-2 Tuesday", true},
+		{"%G-W%V-%u %A", "2021-W09-2 Monday", false},
+	}
+	for _, test := range tests {
+		_, err := ParseChecked(test.format, test.value)
+		if (err == nil) != test.valid {
+			t.Errorf("Given: %v for %q, expected valid: %v", err, test.value, test.valid)
+		}
+		// Parse ignores the weekday.
+		if _, err := Parse(test.format, test.value); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestParseLeapSecond(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		expected time.Time
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2016-12-31 23:59:60", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%Y-%m-%dT%H:%M:%S.%L%z", "2015-06-30T23:59:60.500+0000", time.Date(2015, 7, 1, 0, 0, 0, 500000000, time.UTC)},
+		// The leap second is at 23:59:60 UTC, 00:59:60 in Paris.
+		{"%Y-%m-%d %H:%M:%S %:z", "2017-01-01 00:59:60 +01:00", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%Y-%m-%d %H:%M:%S", "2016-12-31 23:59:59", time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := ParseLeapSecond(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+		} else if !dt.Equal(test.expected) {
+			t.Errorf("Given: %v, expected: %v", dt, test.expected)
+		}
+	}
+
+	if _, err := Parse("%Y-%m-%d %H:%M:%S", "2016-12-31 23:59:60"); err == nil {
+		t.Error("Parse: expected an error for a leap second")
+	}
+	if _, err := ParseLeapSecond("%H:%M:%S", "23:59:61"); err == nil {
+		t.Error("ParseLeapSecond: expected an error for a second of 61")
+	}
+}
+
+func TestParseWithYear(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		year     int
+		expected time.Time
+	}{
+		{Syslog, "Jan  4 05:06:07", 2021, time.Date(2021, 1, 4, 5, 6, 7, 0, time.UTC)},
+		{Syslog, "Feb 29 05:06:07", 2020, time.Date(2020, 2, 29, 5, 6, 7, 0, time.UTC)},
+		{"%m-%d %H:%M %z", "03-04 05:06 +0100", 2021, time.Date(2021, 3, 4, 5, 6, 0, 0, time.FixedZone("", 3600))},
+		{"%j", "060", 2021, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+		// A year directive wins.
+		{"%Y-%m-%d", "2019-01-02", 2021, time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%y-%m-%d", "19-01-02", 2021, time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%C", "19", 2021, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2019-W01-1", 2021, time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := ParseWithYear(test.format, test.value, test.year)
+		if err != nil {
+			t.Error(err)
+		} else if !dt.Equal(test.expected) {
+			t.Errorf("ParseWithYear(%q): given: %v, expected: %v", test.value, dt, test.expected)
+		}
+	}
+
+	if _, err := ParseWithYear(Syslog, "Feb 29 05:06:07", 2021); err == nil {
+		t.Error("ParseWithYear: expected an error for February 29th of a common year")
+	}
+}
+
+func TestParseWithPivot(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		pivot    int
+		expected int
+	}{
+		{"%y-%m-%d", "49-01-01", 50, 2049},
+		{"%y-%m-%d", "50-01-01", 50, 1950},
+		{"%y-%m-%d", "68-01-01", 50, 1968},
+		{"%y-%m-%d", "70-01-01", 50, 1970},
+		{"%y-%m-%d", "00-01-01", 50, 2000},
+		{"%y-%m-%d", "99-01-01", 100, 2099},
+		{"%y-%m-%d", "00-01-01", 0, 1900},
+		{"%d/%m/%y", "01/01/68", 69, 2068},
+		// %C and %Y select the century themselves.
+		{"%C%y-%m-%d", "1949-01-01", 50, 1949},
+		{"%y %Y", "49 1949", 50, 1949},
+	}
+	for _, test := range tests {
+		dt, err := ParseWithPivot(test.format, test.value, test.pivot)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if dt.Year() != test.expected {
+			t.Errorf("Given: %v, expected: %v", dt.Year(), test.expected)
+		}
+	}
+
+	// Parse keeps the pivot of time.Parse.
+	dt, err := Parse("%y-%m-%d", "49-01-01")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Year() != 2049 {
+		t.Errorf("Given: %v, expected: %v", dt.Year(), 2049)
+	}
+
+	for _, pivot := range []int{-1, 101} {
+		if _, err := ParseWithPivot("%y", "49", pivot); err == nil {
+			t.Errorf("ParseWithPivot: expected an error for pivot %d", pivot)
+		}
+	}
+}
+
+func TestParseHour12(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		expected time.Time
+	}{
+		{"%I:%M %p", "03:04 PM", time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC)},
+		{"%I:%M %p", "12:30 AM", time.Date(0, 1, 1, 0, 30, 0, 0, time.UTC)},
+		{"%P %l:%M", "pm 12:30", time.Date(0, 1, 1, 12, 30, 0, 0, time.UTC)},
+		{"%r", "09:15:00 am", time.Date(0, 1, 1, 9, 15, 0, 0, time.UTC)},
+		// %s takes precedence over the other directives.
+		{"%s %I", "1614729600 03", time.Unix(1614729600, 0).UTC()},
+	}
+	for _, test := range tests {
+		dt, err := Parse(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+		} else if !dt.Equal(test.expected) {
+			t.Errorf("Given: %v, expected: %v", dt, test.expected)
+		}
+	}
+
+	for _, format := range []string{"%I:%M", "%l:%M", "%H %-I"} {
+		dt := time.Date(2021, 3, 4, 15, 4, 0, 0, time.UTC)
+		s, err := Format(format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Parse(format, s); err == nil {
+			t.Errorf("Parse(%q, %q): expected an error for an hour without AM/PM", format, s)
+		}
+	}
+}
+
+// failingWriter fails every write with err.
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write(p []byte) (int, error) { return 0, w.err }
+
+func TestFormatTo(t *testing.T) {
+	for _, format := range []string{format1, format2, "%c", "%Y-%m-%dT%H:%M:%S.%N%:z"} {
+		var b strings.Builder
+		b.WriteString("> ")
+		if err := FormatTo(&b, format, dt1); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Format(format, dt1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "> " + s; b.String() != expected {
+			t.Errorf("Given: %v, expected: %v", b.String(), expected)
+		}
+	}
+
+	errWrite := errors.New("write failed")
+	if err := FormatTo(failingWriter{errWrite}, format1, dt1); err != errWrite {
+		t.Errorf("Given: %v, expected: %v", err, errWrite)
+	}
+	var b strings.Builder
+	if err := FormatTo(&b, "%Y-%v", dt1); err == nil {
+		t.Error("FormatTo: expected an error for an unsupported directive")
+	} else if b.Len() != 0 {
+		t.Errorf("FormatTo: expected nothing written, given: %q", b.String())
+	}
+}
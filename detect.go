@@ -0,0 +1,75 @@
+package ctimefmt
+
+import (
+	"fmt"
+	"time"
+)
+
+// detectFormats are the formats Detect tries, most preferred first.
+var detectFormats = []string{
+	// ISO 8601 and RFC 3339
+	"%Y-%m-%dT%H:%M:%SZ",
+	"%Y-%m-%dT%H:%M:%S.%LZ",
+	"%Y-%m-%dT%H:%M:%S.%6fZ",
+	"%Y-%m-%dT%H:%M:%S.%NZ",
+	RFC3339,
+	ISO8601Millis,
+	"%Y-%m-%dT%H:%M:%S.%6f%:z",
+	"%Y-%m-%dT%H:%M:%S.%N%:z",
+	ISO8601,
+	"%Y-%m-%dT%H:%M:%S.%L%z",
+	"%Y-%m-%dT%H:%M:%S",
+	"%Y-%m-%dT%H:%M:%S.%L",
+	"%Y-%m-%dT%H:%M:%S.%6f",
+
+	// SQL-like and application logs
+	DateTime,
+	"%Y-%m-%d %H:%M:%S.%L",
+	"%Y-%m-%d %H:%M:%S,%L",
+	"%Y-%m-%d %H:%M:%S.%6f",
+	"%Y-%m-%d %H:%M:%S %z",
+	"%Y-%m-%d %H:%M:%S %Z",
+	"%Y-%m-%d %H:%M:%S.%L %z",
+	"%Y/%m/%d %H:%M:%S",
+	"%Y-%m-%d",
+
+	// Apache common log
+	ApacheCLF,
+
+	// RFC 1123, RFC 822 and the C library
+	RFC1123,
+	"%a, %d %b %Y %H:%M:%S %z",
+	"%d %b %y %H:%M %Z",
+	"%a %b %e %H:%M:%S %Y",
+	UnixDate,
+
+	// Syslog (RFC 3164)
+	Syslog,
+
+	// US dates
+	"%m/%d/%Y %H:%M:%S",
+	"%m/%d/%Y %I:%M:%S %p",
+	"%m/%d/%Y",
+
+	// Unix epoch
+	"%s",
+}
+
+// Detect guesses the ctime-like format of a sample timestamp. It returns
+// the first of a list of common formats, e.g. RFC 3339, syslog and Apache
+// log timestamps, which parses the sample and formats the parsed time back
+// to the sample. An ambiguous sample, e.g. 01/02/2021, gets the format
+// most commonly used.
+func Detect(sample string) (string, error) {
+	for _, format := range detectFormats {
+		l, err := compile(format)
+		if err != nil {
+			return "", err
+		}
+		t, err := l.parse(sample, time.UTC, time.UTC, parseOptions{})
+		if err == nil && l.Format(t) == sample {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("no known format matches %q", sample)
+}
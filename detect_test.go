@@ -0,0 +1,39 @@
+package ctimefmt
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		sample string
+		format string
+	}{
+		{"2021-03-04T05:06:07Z", "%Y-%m-%dT%H:%M:%SZ"},
+		{"2021-03-04T05:06:07.123Z", "%Y-%m-%dT%H:%M:%S.%LZ"},
+		{"2021-03-04T05:06:07+01:00", "%Y-%m-%dT%H:%M:%S%:z"},
+		{"2021-03-04T05:06:07.123456-07:00", "%Y-%m-%dT%H:%M:%S.%6f%:z"},
+		{"2021-03-04 05:06:07", "%Y-%m-%d %H:%M:%S"},
+		{"2021-03-04 05:06:07,123", "%Y-%m-%d %H:%M:%S,%L"},
+		{"2021-03-04", "%Y-%m-%d"},
+		{"04/Mar/2021:05:06:07 -0700", "%d/%b/%Y:%H:%M:%S %z"},
+		{"Thu, 04 Mar 2021 05:06:07 GMT", "%a, %d %b %Y %H:%M:%S %Z"},
+		{"Thu Mar  4 05:06:07 2021", "%a %b %e %H:%M:%S %Y"},
+		{"Mar  4 05:06:07", "%b %e %H:%M:%S"},
+		{"Mar 14 05:06:07", "%b %e %H:%M:%S"},
+		{"03/04/2021 05:06:07", "%m/%d/%Y %H:%M:%S"},
+		{"1614834367", "%s"},
+	}
+	for _, test := range tests {
+		format, err := Detect(test.sample)
+		if err != nil {
+			t.Error(err)
+		} else if format != test.format {
+			t.Errorf("Detect(%q): given: %v, expected: %v", test.sample, format, test.format)
+		}
+	}
+
+	for _, sample := range []string{"", "yesterday", "2021-13-04", "2021-03-04 05:06:07 extra"} {
+		if format, err := Detect(sample); err == nil {
+			t.Errorf("Detect(%q): given: %v, expected an error", sample, format)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package ctimefmt
+
+import (
+	"sort"
+	"time"
+)
+
+// Directive describes a directive supported by Format and Parse.
+type Directive struct {
+	Token       string // e.g. "%Y"
+	Description string // empty for custom directives
+	Example     string // the reference time formatted with the directive
+}
+
+// referenceTime is the time SupportedDirectives formats the examples with.
+var referenceTime = time.Date(2006, time.January, 2, 15, 4, 5, 123456789, time.FixedZone("MST", -7*3600))
+
+// ctime format -> description, for all of ctimeDirectives and
+// ctimeSubstitutes
+var ctimeDescriptions = map[string]string{
+	"%Y":    "Year, zero-padded",
+	"%:Y":   "Year, zero-padded, parsed with four or two digits",
+	"%y":    "Year, last two digits, zero-padded",
+	"%C":    "Century, the year divided by 100, zero-padded",
+	"%m":    "Month as a zero-padded decimal number",
+	"%o":    "Month as a space-padded decimal number",
+	"%q":    "Quarter of the year",
+	"%b":    "Abbreviated month name",
+	"%h":    "Abbreviated month name, same as %b",
+	"%B":    "Full month name",
+	"%d":    "Day of the month, zero-padded",
+	"%e":    "Day of the month, space-padded",
+	"%:d":   "Day of the month with its English ordinal suffix",
+	"%j":    "Day of the year, zero-padded",
+	"%G":    "ISO 8601 week-based year, zero-padded",
+	"%g":    "ISO 8601 week-based year, last two digits, zero-padded",
+	"%V":    "ISO 8601 week of the year, zero-padded",
+	"%:V":   "ISO 8601 week of the year after -W, same as -W%V",
+	"%U":    "Week of the year starting on Sunday, zero-padded",
+	"%W":    "Week of the year starting on Monday, zero-padded",
+	"%a":    "Abbreviated weekday name",
+	"%A":    "Full weekday name",
+	"%1a":   "Weekday initial, ambiguous as Tuesday and Thursday share T",
+	"%w":    "Weekday as a decimal number, Sunday is 0",
+	"%u":    "ISO 8601 weekday as a decimal number, Monday is 1",
+	"%H":    "Hour (24-hour clock), zero-padded",
+	"%k":    "Hour (24-hour clock), space-padded",
+	"%l":    "Hour (12-hour clock), space-padded",
+	"%I":    "Hour (12-hour clock), zero-padded",
+	"%p":    "AM or PM",
+	"%P":    "am or pm",
+	"%:p":   "A.M. or P.M., parsed in any case",
+	"%:P":   "a.m. or p.m., parsed in any case",
+	"%M":    "Minute, zero-padded",
+	"%S":    "Second, zero-padded",
+	"%-m":   "Month as a decimal number, unpadded",
+	"%-d":   "Day of the month, unpadded",
+	"%_d":   "Day of the month, space-padded, same as %e",
+	"%-I":   "Hour (12-hour clock), unpadded",
+	"%-M":   "Minute, unpadded",
+	"%-S":   "Second, unpadded",
+	"%L":    "Millisecond, zero-padded on the left",
+	"%f":    "Microsecond, trailing zeros omitted",
+	"%1f":   "Fraction of a second with 1 digit",
+	"%2f":   "Fraction of a second with 2 digits",
+	"%3f":   "Fraction of a second with 3 digits",
+	"%4f":   "Fraction of a second with 4 digits",
+	"%5f":   "Fraction of a second with 5 digits",
+	"%6f":   "Fraction of a second with 6 digits",
+	"%7f":   "Fraction of a second with 7 digits",
+	"%8f":   "Fraction of a second with 8 digits",
+	"%9f":   "Fraction of a second with 9 digits",
+	"%-1f":  "Fraction of a second with up to 1 digit, trailing zeros omitted",
+	"%-2f":  "Fraction of a second with up to 2 digits, trailing zeros omitted",
+	"%-3f":  "Fraction of a second with up to 3 digits, trailing zeros omitted",
+	"%-4f":  "Fraction of a second with up to 4 digits, trailing zeros omitted",
+	"%-5f":  "Fraction of a second with up to 5 digits, trailing zeros omitted",
+	"%-6f":  "Fraction of a second with up to 6 digits, trailing zeros omitted",
+	"%-7f":  "Fraction of a second with up to 7 digits, trailing zeros omitted",
+	"%-8f":  "Fraction of a second with up to 8 digits, trailing zeros omitted",
+	"%-9f":  "Fraction of a second with up to 9 digits, trailing zeros omitted",
+	"%N":    "Nanosecond, zero-padded on the left",
+	"%s":    "Seconds since the Unix epoch",
+	"%Q":    "Milliseconds since the Unix epoch",
+	"%3s":   "Milliseconds since the Unix epoch, same as %Q",
+	"%6s":   "Microseconds since the Unix epoch",
+	"%9s":   "Nanoseconds since the Unix epoch",
+	"%Z":    "Timezone name or abbreviation",
+	"%:Z":   "Time zone location name, e.g. America/New_York",
+	"%z":    "UTC offset in the form ±HHMM[SS]",
+	"%:z":   "UTC offset in the form ±HH:MM",
+	"%::z":  "UTC offset in the form ±HH:MM:SS",
+	"%:::z": "UTC offset in the form ±HH[:MM[:SS]], as precise as needed",
+	"%#z":   "UTC offset in the form ±HHMM, or Z for UTC",
+	"%#:z":  "UTC offset in the form ±HH:MM, or Z for UTC",
+	"%Ez":   "UTC offset in the form ±HH:MM, parsed with or without the colon",
+	"%Oz":   "UTC offset in minutes",
+	"%EZ":   "UTC offset after UTC, e.g. UTC+02:00, parsed after UTC or GMT",
+	"%i":    "UTC offset hours in the form ±HH",
+	"%-i":   "UTC offset in whole hours, unpadded, e.g. +7",
+	"%D":    "Short MM/DD/YYYY date, same as %m/%d/%Y",
+	"%x":    "Short MM/DD/YYYY date, same as %m/%d/%Y",
+	"%F":    "Short YYYY-MM-DD date, same as %Y-%m-%d",
+	"%T":    "ISO 8601 time, same as %H:%M:%S",
+	"%X":    "ISO 8601 time, same as %H:%M:%S",
+	"%r":    "12-hour clock time, same as %I:%M:%S %P",
+	"%R":    "24-hour HH:MM time, same as %H:%M",
+	"%n":    "New-line character",
+	"%t":    "Horizontal-tab character",
+	"%%":    "A % sign",
+	"%c":    "Date and time representation, same as %a %b %d %H:%M:%S %Y",
+	"%+":    "Date and time as printed by date(1), same as %a %b %e %H:%M:%S %Z %Y",
+}
+
+// SupportedDirectives lists the built-in and custom directives, sorted by
+// token, with their description and an example formatted from Go's
+// reference time Mon Jan 2 15:04:05.123456789 MST 2006. Of the flagged
+// variants only those with a Go layout equivalent are listed, e.g. %-d.
+func SupportedDirectives() []Directive {
+	var tokens []string
+	for token := range ctimeDescriptions {
+		tokens = append(tokens, token)
+	}
+	customs.mu.RLock()
+	for token := range customs.directives {
+		tokens = append(tokens, token)
+	}
+	customs.mu.RUnlock()
+	sort.Strings(tokens)
+
+	list := make([]Directive, len(tokens))
+	for i, token := range tokens {
+		l, err := Compile(token)
+		if err != nil {
+			// Every directive of the tables compiles on its own.
+			panic(err)
+		}
+		list[i] = Directive{
+			Token:       token,
+			Description: ctimeDescriptions[token],
+			Example:     l.Format(referenceTime),
+		}
+	}
+	return list
+}
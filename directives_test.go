@@ -0,0 +1,51 @@
+package ctimefmt
+
+import "testing"
+
+func TestSupportedDirectives(t *testing.T) {
+	listed := make(map[string]Directive)
+	for _, d := range SupportedDirectives() {
+		listed[d.Token] = d
+	}
+
+	for directive := range ctimeSubstitutes {
+		d, ok := listed[directive]
+		if !ok {
+			t.Errorf("Directive %q is not listed", directive)
+		} else if d.Description == "" {
+			t.Errorf("Directive %q has no description", directive)
+		}
+	}
+	for directive := range ctimeDirectives {
+		d, ok := listed[directive]
+		if !ok {
+			t.Errorf("Directive %q is not listed", directive)
+		} else if d.Description == "" {
+			t.Errorf("Directive %q has no description", directive)
+		}
+	}
+	for directive := range ctimeDescriptions {
+		if _, ok := lookupDirective(directive); !ok {
+			t.Errorf("Description of unknown directive %q", directive)
+		}
+	}
+
+	examples := map[string]string{
+		"%Y":  "2006",
+		"%b":  "Jan",
+		"%e":  " 2",
+		"%-d": "2",
+		"%j":  "002",
+		"%I":  "03",
+		"%L":  "123",
+		"%z":  "-0700",
+		"%Z":  "MST",
+		"%s":  "1136239445",
+		"%c":  "Mon Jan 02 15:04:05 2006",
+	}
+	for directive, expected := range examples {
+		if d := listed[directive]; d.Example != expected {
+			t.Errorf("%v: given: %q, expected: %q", directive, d.Example, expected)
+		}
+	}
+}
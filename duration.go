@@ -0,0 +1,58 @@
+package ctimefmt
+
+import (
+	"fmt"
+	"time"
+)
+
+// durationFields maps the directives meaningful for durations to the
+// field of the duration they render.
+var durationFields = map[string]func(d time.Duration) int{
+	"%H": func(d time.Duration) int { return int(d / time.Hour) },
+	"%M": func(d time.Duration) int { return int(d / time.Minute % 60) },
+	"%S": func(d time.Duration) int { return int(d / time.Second % 60) },
+}
+
+// FormatSince formats the time elapsed since t, e.g. the age of a record
+// as "%Hh %Mm" for 2h 05m. %H is the number of whole hours, which may
+// exceed 23, %M and %S the minutes and seconds past them; with the - and
+// _ flags, e.g. %-H, they are unpadded or space-padded, and %T and %R
+// expand to them as usual. A time in the future renders with a leading
+// minus sign.
+//
+// An error is returned if the format contains an unsupported directive or
+// one which has no meaning for a duration, such as the date directives.
+func FormatSince(format string, t time.Time) (string, error) {
+	return formatDuration(format, time.Since(t))
+}
+
+// formatDuration formats d with the directives of FormatSince.
+func formatDuration(format string, d time.Duration) (string, error) {
+	l, err := compile(format)
+	if err != nil {
+		return "", err
+	}
+	var b []byte
+	if d < 0 {
+		b = append(b, '-')
+		d = -d
+	}
+	for _, c := range l.chunks {
+		if c.d == nil {
+			b = append(b, c.text...)
+			continue
+		}
+		text, pad := stripModifier(c.text), byte('0')
+		if len(text) == 3 {
+			if p, ok := padFlags[text[1]]; ok {
+				text, pad = "%"+text[2:], p
+			}
+		}
+		field, ok := durationFields[text]
+		if !ok {
+			return "", fmt.Errorf("directive %q has no meaning for a duration, expected %%H, %%M or %%S", c.text)
+		}
+		b = appendInt(b, field(d), 2, pad)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,47 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatSince(t *testing.T) {
+	s, err := FormatSince("%Hh %Mm", time.Now().Add(-(2*time.Hour + 5*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "02h 05m" {
+		t.Errorf("Given: %v, expected: %v", s, "02h 05m")
+	}
+
+	tests := []struct {
+		format   string
+		d        time.Duration
+		expected string
+	}{
+		{"%H:%M:%S", 2*time.Hour + 5*time.Minute + 7*time.Second, "02:05:07"},
+		{"%-Hh %-Mm %-Ss", 2*time.Hour + 5*time.Minute + 7*time.Second, "2h 5m 7s"},
+		{"%_H:%M", 3*time.Minute + 999*time.Millisecond, " 0:03"},
+		{"%Hh", 50 * time.Hour, "50h"},
+		{"%H:%M up 100%%", 125 * time.Minute, "02:05 up 100%"},
+		{"%Hh %Mm", -(2*time.Hour + 5*time.Minute), "-02h 05m"},
+		{"%OHh", time.Hour, "01h"},
+		{"%T", 2*time.Hour + 5*time.Minute + 7*time.Second, "02:05:07"},
+	}
+	for _, test := range tests {
+		s, err := formatDuration(test.format, test.d)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	for _, format := range []string{"%Y %H", "%d days", "%r", "%H.%L", "%v"} {
+		if _, err := formatDuration(format, time.Hour); err == nil {
+			t.Errorf("%q: expected an error", format)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package ctimefmt
+
+import "time"
+
+// fastField is a component of a time which a fast layout renders
+// directly.
+type fastField uint8
+
+const (
+	fastLiteral fastField = iota
+	fastYear
+	fastMonth
+	fastDay
+	fastHour
+	fastMinute
+	fastSecond
+	fastFrac
+)
+
+// directive -> component, for the fixed-width numeric directives of fast
+// layouts.
+var fastFields = map[*directive]fastField{
+	year:                  fastYear,
+	ctimeDirectives["%m"]: fastMonth,
+	ctimeDirectives["%d"]: fastDay,
+	ctimeDirectives["%H"]: fastHour,
+	ctimeDirectives["%M"]: fastMinute,
+	seconds:               fastSecond,
+}
+
+// fastChunk is a chunk of a fast layout with the component it renders.
+type fastChunk struct {
+	chunk
+	field fastField
+}
+
+// fastLayout returns the chunks of a layout made of literal text,
+// fractional seconds and the directives of fastFields only, e.g.
+// "%Y-%m-%d %H:%M:%S" or "%FT%T.%L", or nil for any other layout. Such
+// layouts are formatted by appendFast, which computes the date and the
+// clock once instead of once per directive.
+func fastLayout(chunks []chunk) []fastChunk {
+	fast := make([]fastChunk, len(chunks))
+	for i, c := range chunks {
+		fast[i].chunk = c
+		switch {
+		case c.d == nil:
+		case c.d.frac > 0:
+			fast[i].field = fastFrac
+		default:
+			field, ok := fastFields[c.d]
+			if !ok {
+				return nil
+			}
+			fast[i].field = field
+		}
+	}
+	return fast
+}
+
+// appendFast is AppendFormat for a layout with fast chunks. It reports
+// false, leaving b unchanged, for a year which does not take exactly four
+// digits, which AppendFormat renders through the directives instead.
+func (l *Layout) appendFast(b []byte, t time.Time) ([]byte, bool) {
+	year, month, day := t.Date()
+	if year < 0 || year > 9999 {
+		return b, false
+	}
+	hour, min, sec := t.Clock()
+	for _, c := range l.fast {
+		switch c.field {
+		case fastLiteral:
+			b = append(b, c.text...)
+		case fastYear:
+			b = append(b, byte('0'+year/1000), byte('0'+year/100%10), byte('0'+year/10%10), byte('0'+year%10))
+		case fastMonth:
+			b = appendTwoDigits(b, int(month))
+		case fastDay:
+			b = appendTwoDigits(b, day)
+		case fastHour:
+			b = appendTwoDigits(b, hour)
+		case fastMinute:
+			b = appendTwoDigits(b, min)
+		case fastSecond:
+			b = appendTwoDigits(b, sec)
+		case fastFrac:
+			b = appendFrac(b, t.Nanosecond(), c.d.frac, c.d.trim, c.sep)
+		}
+	}
+	return b, true
+}
+
+// appendTwoDigits appends x, which is in [0, 99], as two digits.
+func appendTwoDigits(b []byte, x int) []byte {
+	return append(b, byte('0'+x/10), byte('0'+x%10))
+}
@@ -0,0 +1,80 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFastLayout(t *testing.T) {
+	formats := []string{
+		"%Y-%m-%d %H:%M:%S",
+		"%F %T",
+		"%Y%m%d%H%M%S",
+		"%FT%T.%L",
+		"%Y-%m-%dT%H:%M:%S,%f",
+		"%d/%m/%Y %H:%M:%S.%N",
+		"[%Y-%m-%d %H:%M]",
+		"%Od.%Om.%EY",
+	}
+	times := []time.Time{
+		time.Date(2021, 3, 4, 5, 6, 7, 890123456, time.UTC),
+		time.Date(1999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(2000, 1, 1, 0, 0, 0, 0, time.FixedZone("", -7*60*60)),
+		time.Date(0, 1, 1, 0, 0, 0, 100000000, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+		// Years the fast path does not render.
+		time.Date(-1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, format := range formats {
+		l := MustCompile(format)
+		if l.fast == nil {
+			t.Errorf("%q: expected a fast layout", format)
+			continue
+		}
+		general := *l
+		general.fast = nil
+		for _, tm := range times {
+			if s, expected := l.Format(tm), general.Format(tm); s != expected {
+				t.Errorf("%q: given: %v, expected: %v", format, s, expected)
+			}
+		}
+		if lenient := CompileLenient(format); lenient.fast == nil {
+			t.Errorf("%q: expected a fast lenient layout", format)
+		}
+	}
+
+	for _, format := range []string{"%y-%m-%d", "%-d.%m.%Y", "%Y-%m-%d %z", "%e %b %Y", "%H:%M:%S %p"} {
+		if l := MustCompile(format); l.fast != nil {
+			t.Errorf("%q: expected no fast layout", format)
+		}
+	}
+}
+
+func BenchmarkFastLayoutFormat(b *testing.B) {
+	l := MustCompile("%Y-%m-%d %H:%M:%S")
+	benchmarkLayoutAppend(b, l)
+}
+
+func BenchmarkGeneralLayoutFormat(b *testing.B) {
+	l := *MustCompile("%Y-%m-%d %H:%M:%S")
+	l.fast = nil
+	benchmarkLayoutAppend(b, &l)
+}
+
+func benchmarkLayoutAppend(b *testing.B, l *Layout) {
+	var buf []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = l.AppendFormat(buf[:0], dt1)
+	}
+}
+
+func BenchmarkTimeAppendFormat(b *testing.B) {
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = dt1.AppendFormat(buf[:0], "2006-01-02 15:04:05")
+	}
+}
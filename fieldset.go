@@ -0,0 +1,103 @@
+package ctimefmt
+
+// FieldSet is a set of the components of a time a format sets when
+// parsing, e.g. to merge a date and a time parsed from separate values.
+type FieldSet uint
+
+// The components of a FieldSet.
+const (
+	YearField FieldSet = 1 << iota
+	MonthField
+	DayField
+	HourField
+	MinuteField
+	SecondField
+	FractionField
+	ZoneField
+)
+
+// DateFields and ClockFields are the components of a date and of a time of day.
+const (
+	DateFields  = YearField | MonthField | DayField
+	ClockFields = HourField | MinuteField | SecondField | FractionField
+)
+
+// Has reports whether s holds all the components of t.
+func (s FieldSet) Has(t FieldSet) bool {
+	return s&t == t
+}
+
+// ctime format -> components it sets, for the directives of
+// ctimeDirectives which set any. The name and number of a weekday, %p and
+// the week numbers other than %V only qualify other components.
+var directiveFields = map[string]FieldSet{
+	"%Y":    YearField,
+	"%:Y":   YearField,
+	"%y":    YearField,
+	"%C":    YearField,
+	"%G":    YearField,
+	"%g":    YearField,
+	"%m":    MonthField,
+	"%q":    MonthField,
+	"%b":    MonthField,
+	"%h":    MonthField,
+	"%B":    MonthField,
+	"%d":    DayField,
+	"%e":    DayField,
+	"%:d":   DayField,
+	"%j":    MonthField | DayField,
+	"%V":    MonthField | DayField,
+	"%H":    HourField,
+	"%k":    HourField,
+	"%l":    HourField,
+	"%I":    HourField,
+	"%M":    MinuteField,
+	"%S":    SecondField,
+	"%s":    DateFields | HourField | MinuteField | SecondField,
+	"%Q":    DateFields | ClockFields,
+	"%3s":   DateFields | ClockFields,
+	"%6s":   DateFields | ClockFields,
+	"%9s":   DateFields | ClockFields,
+	"%Z":    ZoneField,
+	"%:Z":   ZoneField,
+	"%z":    ZoneField,
+	"%:z":   ZoneField,
+	"%::z":  ZoneField,
+	"%:::z": ZoneField,
+	"%#z":   ZoneField,
+	"%#:z":  ZoneField,
+	"%Ez":   ZoneField,
+	"%Oz":   ZoneField,
+	"%EZ":   ZoneField,
+	"%i":    ZoneField,
+	"%-i":   ZoneField,
+}
+
+// Fields returns the components of a time format sets when parsing, e.g.
+// DateFields for "%Y-%m-%d" and HourField|MinuteField for "%H:%M". A Unix
+// epoch directive sets the date and the time of day, with FractionField
+// for the units finer than seconds such as %Q. Custom directives set none.
+//
+// An error is returned if the format contains an unsupported directive.
+func Fields(format string) (FieldSet, error) {
+	l, err := compile(format)
+	if err != nil {
+		return 0, err
+	}
+	var s FieldSet
+	for _, c := range l.chunks {
+		switch {
+		case c.d == nil:
+		case c.d.frac > 0:
+			s |= FractionField
+		default:
+			text := stripModifier(c.text)
+			set, ok := directiveFields[text]
+			if !ok && len(text) == 3 {
+				set = directiveFields["%"+text[2:]]
+			}
+			s |= set
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,42 @@
+package ctimefmt
+
+import "testing"
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected FieldSet
+	}{
+		{"%Y-%m-%d", DateFields},
+		{"%F", DateFields},
+		{"%d/%b/%y", DateFields},
+		{"%Y-%j", DateFields},
+		{"%G-W%V-%u", DateFields},
+		{"%H:%M:%S", HourField | MinuteField | SecondField},
+		{"%H:%M:%S.%L", ClockFields},
+		{"%-I:%M %p", HourField | MinuteField},
+		{"%Y-%m-%dT%H:%M:%S.%f%:z", DateFields | ClockFields | ZoneField},
+		{"%c", DateFields | HourField | MinuteField | SecondField},
+		{"%s", DateFields | HourField | MinuteField | SecondField},
+		{"%Q", DateFields | ClockFields},
+		{"%A %U %%", 0},
+		{"%Ey %Od %^b", DateFields},
+	}
+	for _, test := range tests {
+		s, err := Fields(test.format)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%q: given: %b, expected: %b", test.format, s, test.expected)
+		}
+	}
+
+	if s := DateFields | ZoneField; !s.Has(YearField|ZoneField) || s.Has(HourField) || s.Has(DateFields|HourField) {
+		t.Errorf("Given: %b, expected: Has to check every component", s)
+	}
+	if _, err := Fields("%Y-%v"); err == nil {
+		t.Error("Fields: expected an error for an unsupported directive")
+	}
+}
@@ -0,0 +1,57 @@
+package ctimefmt
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Formatter formats times with a single ctime-like format, converted on
+// first use instead of when the Formatter is declared. Unlike
+// MustCompile, declaring a Formatter at package scope does no work at
+// init time, e.g.
+//
+//	var stamp = ctimefmt.NewFormatter("%Y-%m-%d %H:%M:%S")
+//
+// A Formatter is safe for concurrent use.
+type Formatter struct {
+	format string
+	once   sync.Once
+	layout *Layout
+	err    error
+}
+
+// NewFormatter returns a Formatter for the format. The format is not
+// checked until the Formatter is used.
+func NewFormatter(format string) *Formatter {
+	return &Formatter{format: format}
+}
+
+// compile returns the layout of the format, converting it on the first
+// call, and panics if the format contains an unsupported directive.
+func (f *Formatter) compile() *Layout {
+	f.once.Do(func() {
+		f.layout, f.err = Compile(f.format)
+	})
+	if f.err != nil {
+		panic("ctimefmt: Compile(" + strconv.Quote(f.format) + "): " + f.err.Error())
+	}
+	return f.layout
+}
+
+// Format is like the package-level Format for the format of the
+// Formatter, but panics if the format contains an unsupported directive.
+func (f *Formatter) Format(t time.Time) string {
+	return f.compile().Format(t)
+}
+
+// AppendFormat is like Format but appends the text to b and returns the
+// extended buffer.
+func (f *Formatter) AppendFormat(b []byte, t time.Time) []byte {
+	return f.compile().AppendFormat(b, t)
+}
+
+// String returns the ctime-like format of the Formatter.
+func (f *Formatter) String() string {
+	return f.format
+}
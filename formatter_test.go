@@ -0,0 +1,44 @@
+package ctimefmt
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	validFormatter   = NewFormatter("%Y-%m-%d %H:%M:%S")
+	invalidFormatter = NewFormatter("%Y-%v")
+)
+
+func TestFormatter(t *testing.T) {
+	if s, expected := validFormatter.Format(dt1), dt1.Format("2006-01-02 15:04:05"); s != expected {
+		t.Errorf("Given: %v, expected: %v", s, expected)
+	}
+	if b, expected := validFormatter.AppendFormat([]byte("at "), dt1), "at "+dt1.Format("2006-01-02 15:04:05"); string(b) != expected {
+		t.Errorf("Given: %v, expected: %v", string(b), expected)
+	}
+	if s := validFormatter.String(); s != "%Y-%m-%d %H:%M:%S" {
+		t.Errorf("Given: %v, expected: %v", s, "%Y-%m-%d %H:%M:%S")
+	}
+
+	// An invalid format panics on every use.
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() {
+				r := recover()
+				if s, ok := r.(string); !ok || !strings.Contains(s, `"%v"`) {
+					t.Errorf("Given: %v, expected: a panic naming %%v", r)
+				}
+			}()
+			invalidFormatter.Format(dt1)
+		}()
+	}
+}
+
+func BenchmarkFormatter(b *testing.B) {
+	f := NewFormatter(format1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.Format(dt1)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func FuzzToNative(f *testing.F) {
+	for _, format := range []string{
+		format1, "", "%", "%%", "%%%", "100%", "99% done", "%Y-%m-%d %", "%-", "%:::", "%#",
+		"%€", "%\xff", "%-\xe2\x82", "日付 %Y年%m月%d日", "%E", "%O\n", "%10f", "%-Od", "Jan %d",
+	} {
+		f.Add(format)
+	}
+	f.Fuzz(func(t *testing.T, format string) {
+		native, err := ToNative(format)
+		lenient, lenientErr := ToNativeLenient(format)
+		if err == nil && (lenientErr != nil || lenient != native) {
+			t.Errorf("%q: given: %q, %v, expected: %q", format, lenient, lenientErr, native)
+		}
+		// Neither does compiling and formatting panic.
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC)
+		if l, err := Compile(format); err == nil {
+			l.Format(dt)
+		}
+		CompileLenient(format).Format(dt)
+	})
+}
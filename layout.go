@@ -0,0 +1,707 @@
+package ctimefmt
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Layout is a ctime-like format compiled into literal text and
+// directives. Unlike a Go layout it can hold directives which have no
+// Go reference-time equivalent, e.g. %j.
+//
+// Compiling a format once and reusing the Layout avoids converting the
+// format on every call to Format or Parse.
+type Layout struct {
+	format  string
+	chunks  []chunk
+	lenient bool // literal letters match regardless of case
+	fast    []fastChunk
+}
+
+// chunk is either literal text or a single directive.
+type chunk struct {
+	text string     // literal text, or the directive (e.g. "%d")
+	d    *directive // nil for literal text
+	sep  byte       // '.' or ',' preceding a fractional-seconds directive
+}
+
+// directive describes how a ctime directive is rendered and parsed.
+type directive struct {
+	// literal and expand make the directive a shorthand for literal text
+	// or for another ctime format respectively.
+	literal string
+	expand  string
+
+	format func(b []byte, t time.Time) []byte
+	parse  func(value string, f *fields) (string, error)
+
+	// frac is the number of digits of a fractional-seconds directive,
+	// trim drops its trailing zeros like Go's .999 layouts do.
+	frac int
+	trim bool
+
+	// seconds marks %S, which consumes a fractional part following it
+	// when the format has no fractional-seconds directive of its own.
+	seconds bool
+
+	// unpadded lets Parse, but not ParseStrict, accept the number without
+	// its zero padding, e.g. 4 for the 04 of %d, as written by hand.
+	unpadded bool
+
+	// fold, if set, replaces parse in lenient layouts to match the text of
+	// the directive regardless of case, e.g. pm for the PM of %p.
+	fold func(value string, f *fields) (string, error)
+
+	// width, get and set describe a numeric directive, so that flags such
+	// as - can derive a variant with different padding.
+	width int
+	get   func(time.Time) int
+	set   func(*fields, int) error
+}
+
+// ctime format -> directive implementation. The table is never modified,
+// custom directives are held by customs.
+var ctimeDirectives = map[string]*directive{
+	"%Y": year,
+	"%:Y": {
+		format: year.format,
+		parse:  parseFlexibleYear,
+	},
+	"%y": number(2, '0', true, func(t time.Time) int { return t.Year() % 100 }, setShortYear),
+	"%C": number(2, '0', true, func(t time.Time) int { return t.Year() / 100 }, setCentury),
+	"%m": number(2, '0', true, month, setMonth),
+	"%o": {expand: "%_m"},
+	"%q": number(1, '0', true, quarter, setQuarter),
+	"%b": shortMonth,
+	"%h": shortMonth,
+	"%B": {
+		format: func(b []byte, t time.Time) []byte { return append(b, t.Month().String()...) },
+		parse:  parseMonthName(longMonthNames),
+	},
+	"%d": unpadded(number(2, '0', true, time.Time.Day, setDay)),
+	"%e": number(2, ' ', false, time.Time.Day, setDay),
+	"%:d": {
+		format: func(b []byte, t time.Time) []byte {
+			return append(strconv.AppendInt(b, int64(t.Day()), 10), ordinalSuffix(t.Day())...)
+		},
+		parse: parseOrdinalDay,
+	},
+	"%j":  number(3, '0', true, time.Time.YearDay, setYearDay),
+	"%G":  number(4, '0', true, isoYear, setISOYear),
+	"%g":  number(2, '0', true, func(t time.Time) int { return isoYear(t) % 100 }, setShortISOYear),
+	"%V":  number(2, '0', true, isoWeek, setISOWeek),
+	"%:V": {expand: "-W%V"},
+	"%U":  number(2, '0', true, sundayWeek, setWeek),
+	"%W":  number(2, '0', true, mondayWeek, setWeek),
+	"%a": {
+		format: func(b []byte, t time.Time) []byte { return append(b, t.Weekday().String()[:3]...) },
+		parse:  parseWeekdayName(shortDayNames),
+	},
+	"%A": {
+		format: func(b []byte, t time.Time) []byte { return append(b, t.Weekday().String()...) },
+		parse:  parseWeekdayName(longDayNames),
+	},
+	"%1a": weekdayInitial(English.Days),
+	"%w":  number(1, '0', true, func(t time.Time) int { return int(t.Weekday()) }, setWeekday),
+	"%u":  number(1, '0', true, isoWeekday, setISOWeekday),
+	"%H":  number(2, '0', false, time.Time.Hour, setHour),
+	"%k":  number(2, ' ', false, time.Time.Hour, setHour),
+	"%l":  number(2, ' ', false, hour12, setHour12),
+	"%I":  unpadded(number(2, '0', true, hour12, setHour12)),
+	"%p":  meridiem("AM", "PM"),
+	"%P":  meridiem("am", "pm"),
+	"%:p": {
+		format: meridiem("A.M.", "P.M.").format,
+		parse:  parseDottedMeridiem,
+	},
+	"%:P": {
+		format: meridiem("a.m.", "p.m.").format,
+		parse:  parseDottedMeridiem,
+	},
+	"%M":  number(2, '0', true, time.Time.Minute, setMinute),
+	"%S":  seconds,
+	"%L":  {frac: 3},
+	"%f":  {frac: 6, trim: true},
+	"%1f": {frac: 1},
+	"%2f": {frac: 2},
+	"%3f": {frac: 3},
+	"%4f": {frac: 4},
+	"%5f": {frac: 5},
+	"%6f": {frac: 6},
+	"%7f": {frac: 7},
+	"%8f": {frac: 8},
+	"%9f": {frac: 9},
+
+	// Like Go's .999 layouts the - flag drops trailing zeros.
+	"%-1f": {frac: 1, trim: true},
+	"%-2f": {frac: 2, trim: true},
+	"%-3f": {frac: 3, trim: true},
+	"%-4f": {frac: 4, trim: true},
+	"%-5f": {frac: 5, trim: true},
+	"%-6f": {frac: 6, trim: true},
+	"%-7f": {frac: 7, trim: true},
+	"%-8f": {frac: 8, trim: true},
+	"%-9f": {frac: 9, trim: true},
+
+	"%N":  {frac: 9},
+	"%s":  epoch(1),
+	"%Q":  epoch(1e3),
+	"%3s": epoch(1e3),
+	"%6s": epoch(1e6),
+	"%9s": epoch(1e9),
+	"%Z": {
+		format: appendZoneName,
+		parse:  parseZoneName,
+	},
+	"%z":    fullOffset,
+	"%:z":   offset("-07:00", 2, true),
+	"%::z":  offset("-07:00:00", 3, true),
+	"%:::z": adaptiveOffset,
+	"%#z":   offset("Z0700", 2, false),
+	"%#:z":  offset("Z07:00", 2, true),
+	"%Ez":   lenientOffset,
+	"%Oz":   minuteOffset,
+	"%EZ":   prefixedOffset,
+	"%:Z":   zoneLocation,
+	"%i":    offset("-07", 1, false),
+	"%-i":   hourOffset,
+	"%D":    {expand: "%m/%d/%Y"},
+	"%x":    {expand: "%m/%d/%Y"},
+	"%F":    {expand: "%Y-%m-%d"},
+	"%T":    {expand: "%H:%M:%S"},
+	"%X":    {expand: "%H:%M:%S"},
+	"%r":    {expand: "%I:%M:%S %P"},
+	"%R":    {expand: "%H:%M"},
+	"%n":    {literal: "\n"},
+	"%t":    {literal: "\t"},
+	"%%":    {literal: "%"},
+	"%c":    {expand: "%a %b %d %H:%M:%S %Y"},
+	"%+":    {expand: "%a %b %e %H:%M:%S %Z %Y"},
+}
+
+// year is %Y, which renders years before 1 astronomically, year 0 being
+// 1 BC, with a minus sign before the four digits, e.g. -0001 for 2 BC, and
+// years after 9999 with all their digits.
+var year = func() *directive {
+	d := number(4, '0', true, time.Time.Year, setYear)
+	d.parse = parseYear
+	return d
+}()
+
+var shortMonth = &directive{
+	format: func(b []byte, t time.Time) []byte { return append(b, t.Month().String()[:3]...) },
+	parse:  parseMonthName(shortMonthNames),
+}
+
+var seconds = func() *directive {
+	d := number(2, '0', true, time.Time.Second, setSecond)
+	d.seconds = true
+	return d
+}()
+
+// unpadded sets the unpadded flag of the numeric directive d.
+func unpadded(d *directive) *directive {
+	d.unpadded = true
+	return d
+}
+
+func month(t time.Time) int {
+	return int(t.Month())
+}
+
+func quarter(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
+
+// ordinalSuffix returns the English ordinal suffix of n, e.g. st for 1
+// and 21 but th for 11.
+func ordinalSuffix(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+func isoYear(t time.Time) int {
+	year, _ := t.ISOWeek()
+	return year
+}
+
+func isoWeek(t time.Time) int {
+	_, week := t.ISOWeek()
+	return week
+}
+
+func isoWeekday(t time.Time) int {
+	if wd := t.Weekday(); wd != time.Sunday {
+		return int(wd)
+	}
+	return 7
+}
+
+// sundayWeek returns the week of the year of t, where weeks start on
+// Sunday and the days before the first Sunday are in week 0.
+func sundayWeek(t time.Time) int {
+	return (t.YearDay() + 6 - int(t.Weekday())) / 7
+}
+
+// mondayWeek returns the week of the year of t, where weeks start on
+// Monday and the days before the first Monday are in week 0.
+func mondayWeek(t time.Time) int {
+	return (t.YearDay() + 6 - (int(t.Weekday())+6)%7) / 7
+}
+
+func hour12(t time.Time) int {
+	if h := t.Hour() % 12; h != 0 {
+		return h
+	}
+	return 12
+}
+
+// number returns a directive for a decimal field padded with pad to
+// width digits. Parsing accepts exactly width digits if fixed is set and
+// one to width digits otherwise.
+func number(width int, pad byte, fixed bool, get func(time.Time) int, set func(*fields, int) error) *directive {
+	return &directive{
+		format: func(b []byte, t time.Time) []byte { return appendInt(b, get(t), width, pad) },
+		parse:  parseNumber(width, pad, fixed, set),
+		width:  width,
+		get:    get,
+		set:    set,
+	}
+}
+
+// padFlags maps the flags of numeric directives to the padding they select.
+var padFlags = map[byte]byte{
+	'-': 0,
+	'_': ' ',
+}
+
+// baseDirective returns the built-in or custom directive named text.
+func baseDirective(text string) (*directive, bool) {
+	if d, ok := ctimeDirectives[text]; ok {
+		return d, true
+	}
+	return customs.lookup(text)
+}
+
+// lookupDirective returns the directive for text, deriving flagged
+// variants, e.g. %-d and %_d from %d or %^A from %A.
+func lookupDirective(text string) (*directive, bool) {
+	text = stripModifier(text)
+	if d, ok := baseDirective(text); ok {
+		return d, true
+	}
+	if len(text) <= 2 {
+		return nil, false
+	}
+	d, ok := baseDirective("%" + text[2:])
+	if !ok {
+		return nil, false
+	}
+	if pad, ok := padFlags[text[1]]; ok && d.get != nil {
+		// Parsing accepts one to width digits, as with Go's _2.
+		p := number(d.width, pad, false, d.get, d.set)
+		p.seconds = d.seconds
+		return p, true
+	}
+	if text[1] == '^' && d.format != nil {
+		return upper(d), true
+	}
+	return nil, false
+}
+
+// upper returns a variant of d rendering its text in upper case. Parsing
+// of names is case-insensitive already, and numbers keep their width.
+func upper(d *directive) *directive {
+	u := *d
+	u.format = func(b []byte, t time.Time) []byte {
+		n := len(b)
+		b = d.format(b, t)
+		return append(b[:n], strings.ToUpper(string(b[n:]))...)
+	}
+	return &u
+}
+
+func meridiem(am, pm string) *directive {
+	return &directive{
+		format: func(b []byte, t time.Time) []byte {
+			if t.Hour() >= 12 {
+				return append(b, pm...)
+			}
+			return append(b, am...)
+		},
+		parse: parseMeridiem(am, pm),
+		fold:  parseMeridiemFold,
+	}
+}
+
+// epoch returns a directive for the time since the Unix epoch in units of
+// 1/perSecond seconds, e.g. 1e3 for milliseconds.
+func epoch(perSecond int64) *directive {
+	return &directive{
+		format: func(b []byte, t time.Time) []byte {
+			n := t.Unix()*perSecond + int64(t.Nanosecond())/(1e9/perSecond)
+			return strconv.AppendInt(b, n, 10)
+		},
+		parse: parseEpoch(perSecond),
+	}
+}
+
+// offset returns a directive for a numeric UTC offset rendered with the
+// given Go layout and made of parts (hours, minutes, seconds) numbers.
+func offset(native string, parts int, colon bool) *directive {
+	return &directive{
+		format: func(b []byte, t time.Time) []byte { return t.AppendFormat(b, native) },
+		parse:  parseOffset(parts, colon),
+	}
+}
+
+// fullOffset is %z, which like -0700 renders hours and minutes, followed
+// by the seconds of offsets which have them, e.g. the local mean time of
+// historical zones.
+var fullOffset = &directive{
+	format: func(b []byte, t time.Time) []byte {
+		_, offset := t.Zone()
+		if offset%60 == 0 {
+			return t.AppendFormat(b, "-0700")
+		}
+		return t.AppendFormat(b, "-070000")
+	},
+	parse: parseFullOffset,
+}
+
+// adaptiveOffset is %:::z, which like GNU date renders the offset with as
+// many colon-separated parts as it needs, e.g. +07, +05:30 or -00:17:30.
+var adaptiveOffset = &directive{
+	format: func(b []byte, t time.Time) []byte {
+		_, offset := t.Zone()
+		switch {
+		case offset%60 != 0:
+			return t.AppendFormat(b, "-07:00:00")
+		case offset%3600 != 0:
+			return t.AppendFormat(b, "-07:00")
+		}
+		return t.AppendFormat(b, "-07")
+	},
+	parse: parseAdaptiveOffset,
+}
+
+// lenientOffset is %Ez, which renders the colon form of the offset but
+// parses it with or without the colon, for logs mixing both.
+var lenientOffset = &directive{
+	format: func(b []byte, t time.Time) []byte { return t.AppendFormat(b, "-07:00") },
+	parse:  parseLenientOffset,
+}
+
+// minuteOffset is %Oz, the offset as a signed number of minutes, e.g.
+// +420 for +07:00.
+var minuteOffset = &directive{
+	format: func(b []byte, t time.Time) []byte {
+		_, offset := t.Zone()
+		if offset < 0 {
+			b = append(b, '-')
+			offset = -offset
+		} else {
+			b = append(b, '+')
+		}
+		return strconv.AppendInt(b, int64(offset/60), 10)
+	},
+	parse: parseMinuteOffset,
+}
+
+// hourOffset is %-i, the offset as a signed number of whole hours, e.g.
+// +7 for +07:00. The minutes of sub-hour offsets are dropped, so -05:30
+// renders as -5.
+var hourOffset = &directive{
+	format: func(b []byte, t time.Time) []byte {
+		_, offset := t.Zone()
+		if offset < 0 {
+			b = append(b, '-')
+			offset = -offset
+		} else {
+			b = append(b, '+')
+		}
+		return strconv.AppendInt(b, int64(offset/3600), 10)
+	},
+	parse: parseHourOffset,
+}
+
+// prefixedOffset is %EZ, the offset written after UTC, e.g. UTC+02:00, or
+// UTC alone for a zero offset.
+var prefixedOffset = &directive{
+	format: func(b []byte, t time.Time) []byte {
+		b = append(b, "UTC"...)
+		if _, offset := t.Zone(); offset != 0 {
+			b = t.AppendFormat(b, "-07:00")
+		}
+		return b
+	},
+	parse: parsePrefixedOffset,
+}
+
+// zoneLocation is %:Z, the name of the location, e.g. America/New_York,
+// or like %Z the zone abbreviation for a location without a name.
+var zoneLocation = &directive{
+	format: func(b []byte, t time.Time) []byte {
+		if name := t.Location().String(); name != "" {
+			return append(b, name...)
+		}
+		return t.AppendFormat(b, "MST")
+	},
+	parse: parseLocationName,
+}
+
+// Compile converts a ctime-like format into a Layout.
+//
+// Refer to Format() function documentation for possible directives.
+func Compile(format string) (*Layout, error) {
+	l := &Layout{format: format}
+	if err := l.add(format, 0, false); err != nil {
+		return nil, err
+	}
+	l.fast = fastLayout(l.chunks)
+	return l, nil
+}
+
+// MustCompile is like Compile but panics if the format contains an
+// unsupported directive. It simplifies declaring layouts of known-good
+// formats at package scope, e.g.
+//
+//	var dateLayout = ctimefmt.MustCompile("%Y-%m-%d")
+func MustCompile(format string) *Layout {
+	l, err := Compile(format)
+	if err != nil {
+		panic("ctimefmt: Compile(" + strconv.Quote(format) + "): " + err.Error())
+	}
+	return l
+}
+
+// CompileLenient is like Compile but treats unsupported directives, e.g.
+// the "% " of "100% done", as literal text instead of returning an error.
+// Parsing with the layout also matches the letters of literal text and
+// of %p and %P regardless of case, e.g. "%d %b at %I:%M %p" accepts
+// "04 Mar AT 05:06 pm", except with ParseStrict.
+func CompileLenient(format string) *Layout {
+	l := &Layout{format: format, lenient: true}
+	l.add(format, 0, true)
+	l.fast = fastLayout(l.chunks)
+	return l
+}
+
+// add appends the chunks of format to l. The offset of format within
+// the top-level format is used to report errors. With lenient set
+// unsupported directives are literal text.
+func (l *Layout) add(format string, offset int, lenient bool) error {
+	last := 0
+	for start, end := nextDirective(format, 0); start >= 0; start, end = nextDirective(format, end) {
+		l.addLiteral(format[last:start])
+		last = end
+
+		text := format[start:end]
+		d, ok := lookupDirective(text)
+		switch {
+		case !ok && lenient:
+			l.addLiteral(text)
+		case !ok:
+			return unsupported(text, offset+start)
+		case d.literal != "":
+			l.addLiteral(d.literal)
+		case d.expand != "":
+			if err := l.add(d.expand, offset+start, lenient); err != nil {
+				return err
+			}
+		default:
+			c := chunk{text: text, d: d}
+			if n := len(l.chunks) - 1; d.frac > 0 && n >= 0 && l.chunks[n].d == nil {
+				// Like Go's .000 and .999 layouts, the separator belongs to
+				// the fractional seconds so that .999 may drop it.
+				lit := l.chunks[n].text
+				if sep := lit[len(lit)-1]; sep == '.' || sep == ',' {
+					c.sep = sep
+					if lit = lit[:len(lit)-1]; lit == "" {
+						l.chunks = l.chunks[:n]
+					} else {
+						l.chunks[n].text = lit
+					}
+				}
+			}
+			l.chunks = append(l.chunks, c)
+		}
+	}
+	l.addLiteral(format[last:])
+	return nil
+}
+
+// addLiteral appends literal text, merging it with a preceding literal.
+func (l *Layout) addLiteral(s string) {
+	if s == "" {
+		return
+	}
+	if n := len(l.chunks) - 1; n >= 0 && l.chunks[n].d == nil {
+		l.chunks[n].text += s
+		return
+	}
+	l.chunks = append(l.chunks, chunk{text: s})
+}
+
+// Format returns a textual representation of the time value formatted
+// according to the layout.
+func (l *Layout) Format(t time.Time) string {
+	return string(l.AppendFormat(nil, t))
+}
+
+// formatBuffers holds the buffers FormatTo renders into before writing.
+var formatBuffers = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+// FormatTo writes the textual representation of the time value formatted
+// according to the layout to w, see the package-level FormatTo.
+func (l *Layout) FormatTo(w io.Writer, t time.Time) error {
+	buf := formatBuffers.Get().(*[]byte)
+	*buf = l.AppendFormat((*buf)[:0], t)
+	_, err := w.Write(*buf)
+	formatBuffers.Put(buf)
+	return err
+}
+
+// Parse parses a formatted string and returns the time value it
+// represents.
+func (l *Layout) Parse(value string) (time.Time, error) {
+	return l.parse(value, time.UTC, time.Local, parseOptions{})
+}
+
+// ParseStrict is like Parse but requires the value to match the layout
+// exactly, see the package-level ParseStrict.
+func (l *Layout) ParseStrict(value string) (time.Time, error) {
+	return l.parse(value, time.UTC, time.Local, parseOptions{strict: true})
+}
+
+// ParseChecked is like Parse but verifies a parsed weekday against the
+// parsed date, see the package-level ParseChecked.
+func (l *Layout) ParseChecked(value string) (time.Time, error) {
+	return l.parse(value, time.UTC, time.Local, parseOptions{checkWeekday: true})
+}
+
+// ParseLeapSecond is like Parse but accepts a leap second, see the
+// package-level ParseLeapSecond.
+func (l *Layout) ParseLeapSecond(value string) (time.Time, error) {
+	return l.parse(value, time.UTC, time.Local, parseOptions{leapSecond: true})
+}
+
+// ParseWithYear is like Parse but uses the given year for a layout without
+// a year directive, see the package-level ParseWithYear.
+func (l *Layout) ParseWithYear(value string, year int) (time.Time, error) {
+	return l.parse(value, time.UTC, time.Local, parseOptions{hasYear: true, year: year})
+}
+
+// ParseWithPivot is like Parse but places a two-digit %y year by the given
+// pivot, see the package-level ParseWithPivot.
+func (l *Layout) ParseWithPivot(value string, pivotYear int) (time.Time, error) {
+	if pivotYear < 0 || pivotYear > 100 {
+		return time.Time{}, fmt.Errorf("pivot year %d out of range [0, 100]", pivotYear)
+	}
+	return l.parse(value, time.UTC, time.Local, parseOptions{pivot: true, pivotYear: pivotYear})
+}
+
+// String returns the ctime-like format the layout was compiled from.
+func (l *Layout) String() string {
+	return l.format
+}
+
+// AppendFormat is like Format but appends the textual representation to b
+// and returns the extended buffer.
+//
+// Layouts of only %Y, %m, %d, %H, %M, %S, fractional seconds and literal
+// text, e.g. "%Y-%m-%d %H:%M:%S", are rendered by a specialized formatter
+// which computes the date and the clock once, about twice as fast as the
+// directives and as time.Format.
+func (l *Layout) AppendFormat(b []byte, t time.Time) []byte {
+	if l.fast != nil {
+		if fast, ok := l.appendFast(b, t); ok {
+			return fast
+		}
+	}
+	for _, c := range l.chunks {
+		switch {
+		case c.d == nil:
+			b = append(b, c.text...)
+		case c.d.frac > 0:
+			b = appendFrac(b, t.Nanosecond(), c.d.frac, c.d.trim, c.sep)
+		default:
+			b = c.d.format(b, t)
+		}
+	}
+	return b
+}
+
+// fracAfter reports whether a fractional-seconds directive follows the
+// i-th chunk.
+func (l *Layout) fracAfter(i int) bool {
+	for _, c := range l.chunks[i+1:] {
+		if c.d != nil {
+			return c.d.frac > 0
+		}
+	}
+	return false
+}
+
+// appendInt appends the decimal form of x padded with pad to width.
+func appendInt(b []byte, x int, width int, pad byte) []byte {
+	if x < 0 {
+		b = append(b, '-')
+		x = -x
+	}
+	var buf [20]byte
+	i := len(buf)
+	for x >= 10 {
+		i--
+		buf[i] = byte('0' + x%10)
+		x /= 10
+	}
+	i--
+	buf[i] = byte('0' + x)
+	if pad != 0 {
+		for w := len(buf) - i; w < width; w++ {
+			b = append(b, pad)
+		}
+	}
+	return append(b, buf[i:]...)
+}
+
+// appendFrac appends the first digits of nsec preceded by sep, if any.
+// With trim set trailing zeros are dropped, together with sep when no
+// digit remains.
+func appendFrac(b []byte, nsec int, digits int, trim bool, sep byte) []byte {
+	var buf [9]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = byte('0' + nsec%10)
+		nsec /= 10
+	}
+	frac := buf[:digits]
+	if trim {
+		for len(frac) > 0 && frac[len(frac)-1] == '0' {
+			frac = frac[:len(frac)-1]
+		}
+		if len(frac) == 0 {
+			return b
+		}
+	}
+	if sep != 0 {
+		b = append(b, sep)
+	}
+	return append(b, frac...)
+}
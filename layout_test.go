@@ -0,0 +1,1665 @@
+package ctimefmt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+var equivalenceTimes = []time.Time{
+	time.Date(2019, 1, 2, 15, 4, 5, 666666000, time.UTC),
+	time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+	time.Date(1999, 7, 9, 12, 30, 59, 500000000, time.FixedZone("EST", -5*3600)),
+	time.Date(2005, 11, 20, 9, 7, 1, 123456789, time.FixedZone("", 5*3600+1800)),
+}
+
+// Directives which have a Go layout equivalent must format and parse
+// exactly like the Go layout does.
+func TestNativeEquivalence(t *testing.T) {
+	var formats []string
+	for directive := range ctimeSubstitutes {
+		switch directive {
+		case "%L", "%f", "%N", "%1f", "%2f", "%3f", "%4f", "%5f", "%6f", "%7f", "%8f", "%9f",
+			"%-1f", "%-2f", "%-3f", "%-4f", "%-5f", "%-6f", "%-7f", "%-8f", "%-9f":
+			formats = append(formats, "%S."+directive, "%S,"+directive, "%S."+directive+"%z")
+		case "%I", "%-I":
+			// Parse rejects a 12-hour clock hour without AM/PM.
+			formats = append(formats, "["+directive+" %p]")
+		default:
+			formats = append(formats, "["+directive+"]")
+		}
+	}
+	formats = append(formats,
+		"%Y-%m-%d %H:%M:%S",
+		"%Y-%m-%d %H:%M:%S.%f %z",
+		"%a, %d %b %Y %H:%M:%S %Z",
+		"%A %B %e %I:%M %p",
+		"%c",
+		"%D %r",
+	)
+
+	for _, format := range formats {
+		native, err := ToNative(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, dt := range equivalenceTimes {
+			s, err := Format(format, dt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := dt.Format(native); s != expected {
+				t.Errorf("Format(%q): given: %q, expected: %q", format, s, expected)
+			}
+
+			parsed, err := Parse(format, s)
+			expected, expectedErr := time.Parse(native, s)
+			if (err == nil) != (expectedErr == nil) {
+				t.Errorf("Parse(%q, %q): given error: %v, expected error: %v", format, s, err, expectedErr)
+			} else if err == nil && !parsed.Equal(expected) {
+				t.Errorf("Parse(%q, %q): given: %v, expected: %v", format, s, parsed, expected)
+			} else if err == nil && parsed.Location().String() != expected.Location().String() {
+				t.Errorf("Parse(%q, %q): given location: %v, expected: %v", format, s, parsed.Location(), expected.Location())
+			}
+		}
+	}
+}
+
+func TestFormatDayOfYear(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "001"},
+		{time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), "060"},
+		{time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), "366"},
+		{time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), "365"},
+	}
+	for _, test := range tests {
+		s, err := Format("%j", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+}
+
+func TestParseDayOfYear(t *testing.T) {
+	dt, err := Parse("%Y-%j %H:%M", "2020-060 10:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2020, 2, 29, 10, 20, 0, 0, time.UTC); dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2020-001", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2021-060", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2020-366", time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"2021-365", time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"2000-060", time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"1900-060", time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := Parse("%Y-%j", test.value)
+		if err != nil {
+			t.Error(err)
+		} else if dt != test.expected {
+			t.Errorf("Given: %v, expected: %v", dt, test.expected)
+		}
+	}
+
+	// 2021 is not a leap year.
+	for _, value := range []string{"2021-366", "2020-367", "2020-400", "2020-000"} {
+		if _, err := Parse("%Y-%j", value); err == nil {
+			t.Errorf("Parse(%q): expected an error for an out of range day-of-year", value)
+		}
+	}
+
+	if _, err := ToNative("%Y-%j"); err == nil {
+		t.Error("ToNative: expected an error for %j")
+	}
+}
+
+func TestCentury(t *testing.T) {
+	tests := []struct {
+		year     int
+		expected string
+	}{
+		{99, "00 99"},
+		{1999, "19 99"},
+		{2000, "20 00"},
+		{2021, "20 21"},
+	}
+	for _, test := range tests {
+		dt := time.Date(test.year, 3, 1, 0, 0, 0, 0, time.UTC)
+		s, err := Format("%C %y", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+
+		dt_, err := Parse("%C %y %m", s+" 03")
+		if err != nil {
+			t.Error(err)
+		} else if dt_ != dt {
+			t.Errorf("Given: %v, expected: %v", dt_, dt)
+		}
+	}
+
+	// The century applies regardless of the order of %C and %y.
+	dt, err := Parse("%y%%%C", "68%21")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Year() != 2168 {
+		t.Errorf("Given: %v, expected: %v", dt.Year(), 2168)
+	}
+
+	dt, err = Parse("%C", "19")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Year() != 1900 {
+		t.Errorf("Given: %v, expected: %v", dt.Year(), 1900)
+	}
+}
+
+func TestQuarter(t *testing.T) {
+	tests := []struct {
+		month    time.Month
+		day      int
+		expected string
+	}{
+		{time.January, 1, "2021-Q1"},
+		{time.March, 31, "2021-Q1"},
+		{time.April, 1, "2021-Q2"},
+		{time.June, 30, "2021-Q2"},
+		{time.July, 1, "2021-Q3"},
+		{time.September, 30, "2021-Q3"},
+		{time.October, 1, "2021-Q4"},
+		{time.December, 31, "2021-Q4"},
+	}
+	for _, test := range tests {
+		s, err := Format("%Y-Q%q", time.Date(2021, test.month, test.day, 23, 59, 59, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	for q, month := range []time.Month{time.January, time.April, time.July, time.October} {
+		dt, err := Parse("%Y-Q%q", fmt.Sprintf("2021-Q%d", q+1))
+		if err != nil {
+			t.Error(err)
+		} else if expected := time.Date(2021, month, 1, 0, 0, 0, 0, time.UTC); dt != expected {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+	}
+
+	// A parsed month takes precedence over the quarter.
+	dt, err := Parse("Q%q %m", "Q2 05")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Month() != time.May {
+		t.Errorf("Given: %v, expected: %v", dt.Month(), time.May)
+	}
+
+	if _, err := Parse("%q", "5"); err == nil {
+		t.Error("Parse: expected an error for quarter 5")
+	}
+}
+
+func TestFormatISOWeek(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		// January 1st belonging to the last week of the previous year.
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "2020-W53 20"},
+		{time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), "2020-W53 20"},
+		{time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), "2021-W01 21"},
+		{time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), "2015-W53 15"},
+		// December 31st belonging to week 01 of the next year.
+		{time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC), "2020-W01 20"},
+		{time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "2025-W01 25"},
+		{time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), "2020-W53 20"},
+		{time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), "2018-W01 18"},
+	}
+	for _, test := range tests {
+		s, err := Format("%G-W%V %g", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+}
+
+func TestFormatWeekOfYear(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		// 2017 starts on a Sunday, 2018 on a Monday.
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), "01 00"},
+		{time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC), "01 01"},
+		{time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC), "53 52"},
+		{time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), "00 01"},
+		{time.Date(2018, 1, 6, 0, 0, 0, 0, time.UTC), "00 01"},
+		{time.Date(2018, 1, 7, 0, 0, 0, 0, time.UTC), "01 01"},
+		{time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC), "52 53"},
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "00 00"},
+		{time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), "52 52"},
+	}
+	for _, test := range tests {
+		s, err := Format("%U %W", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Format(%v): given: %v, expected: %v", test.dt, s, test.expected)
+		}
+	}
+}
+
+func TestFormatWeekdayNumber(t *testing.T) {
+	// 2021-03-07 is a Sunday.
+	expected := []string{"0 7 Sun", "1 1 Mon", "2 2 Tue", "3 3 Wed", "4 4 Thu", "5 5 Fri", "6 6 Sat"}
+	for i, e := range expected {
+		dt := time.Date(2021, 3, 7+i, 0, 0, 0, 0, time.UTC)
+		s, err := Format("%w %u %a", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != e {
+			t.Errorf("Given: %v, expected: %v", s, e)
+		}
+		if _, err := Parse("%w %u %a", s); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := Parse("%u", "0"); err == nil {
+		t.Error("Parse: expected an error for weekday 0 with %u")
+	}
+	if _, err := Parse("%w", "7"); err == nil {
+		t.Error("Parse: expected an error for weekday 7 with %w")
+	}
+}
+
+func TestSpacePaddedHour(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2019, 1, 2, 0, 5, 0, 0, time.UTC), " 0|12"},
+		{time.Date(2019, 1, 2, 9, 5, 0, 0, time.UTC), " 9| 9"},
+		{time.Date(2019, 1, 2, 12, 5, 0, 0, time.UTC), "12|12"},
+		{time.Date(2019, 1, 2, 21, 5, 0, 0, time.UTC), "21| 9"},
+	}
+	for _, test := range tests {
+		s, err := Format("%k|%l", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+	}
+
+	for _, value := range []string{" 9:05", "9:05", "09:05"} {
+		dt, err := Parse("%k:%M", value)
+		if err != nil {
+			t.Error(err)
+		} else if dt.Hour() != 9 {
+			t.Errorf("Given: %v, expected: %v", dt.Hour(), 9)
+		}
+	}
+
+	dt, err := Parse("%l:%M %p", " 9:05 PM")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Hour() != 21 {
+		t.Errorf("Given: %v, expected: %v", dt.Hour(), 21)
+	}
+}
+
+// A legacy report time, %r with a space-padded hour.
+func TestSpacePaddedClock(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC), " 3:04:05 am"},
+		{time.Date(2019, 1, 2, 15, 4, 5, 0, time.UTC), " 3:04:05 pm"},
+		{time.Date(2019, 1, 2, 0, 4, 5, 0, time.UTC), "12:04:05 am"},
+		{time.Date(2019, 1, 2, 22, 4, 5, 0, time.UTC), "10:04:05 pm"},
+	}
+	for _, test := range tests {
+		s, err := Format("%l:%M:%S %P", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+
+		dt, err := Parse("%l:%M:%S %P", s)
+		if expected := time.Date(0, 1, 1, test.dt.Hour(), 4, 5, 0, time.UTC); err != nil || dt != expected {
+			t.Errorf("Parse(%q): given: %v, %v, expected: %v", s, dt, err, expected)
+		}
+	}
+}
+
+func TestNoPadding(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC), "2/1/2019 3:4:5 3 AM 2"},
+		{time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), "31/12/2020 0:0:0 12 AM 366"},
+		{time.Date(2021, 10, 9, 15, 30, 59, 0, time.UTC), "9/10/2021 15:30:59 3 PM 282"},
+	}
+	for _, test := range tests {
+		s, err := Format("%-d/%-m/%Y %-H:%-M:%-S %-I %p %-j", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+
+		dt, err := Parse("%-d/%-m/%Y %-H:%-M:%-S %-I %p %-j", s)
+		if err != nil {
+			t.Error(err)
+		} else if dt != test.dt {
+			t.Errorf("Given: %v, expected: %v", dt, test.dt)
+		}
+	}
+
+	if _, err := Parse("%-d", "123"); err == nil {
+		t.Error("Parse: expected an error for a three-digit unpadded day")
+	}
+	if _, err := Format("%-v", time.Now()); err == nil || err.Error() != `unsupported directive "%-v" at offset 0` {
+		t.Errorf("Given: %v, expected: unsupported directive error", err)
+	}
+	if _, err := Format("%-D", time.Now()); err == nil {
+		t.Error("Format: expected an error for an unpadded non-numeric directive")
+	}
+}
+
+func TestSpacePadding(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC), " 2| 1| 3| 3| 1"},
+		{time.Date(2020, 12, 31, 15, 0, 0, 0, time.UTC), "31|12|15| 3|12"},
+	}
+	for _, test := range tests {
+		s, err := Format("%_d|%_m|%_H|%_I|%o", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+	}
+
+	for _, value := range []string{" 2 9", "2 9", "02 09"} {
+		dt, err := Parse("%_d %_H", value)
+		if err != nil {
+			t.Error(err)
+		} else if dt.Day() != 2 || dt.Hour() != 9 {
+			t.Errorf("Given: %v, expected: day 2 and hour 9", dt)
+		}
+	}
+
+	if native, err := ToNative("%_d"); err != nil {
+		t.Error(err)
+	} else if native != "_2" {
+		t.Errorf("Given: %v, expected: %v", native, "_2")
+	}
+}
+
+func TestUppercase(t *testing.T) {
+	dt := time.Date(2019, 1, 7, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%^A %Y", "MONDAY 2019"},
+		{"%^a %A", "MON Monday"},
+		{"%^B %b %^b", "JANUARY Jan JAN"},
+		{"%I %^p %^P", "03 PM PM"},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+	}
+
+	dt_, err := Parse("%^A %d %^B %Y %H:%M:%S", "MONDAY 07 JANUARY 2019 15:04:05")
+	if err != nil {
+		t.Error(err)
+	} else if dt_ != dt {
+		t.Errorf("Given: %v, expected: %v", dt_, dt)
+	}
+}
+
+func TestColonOffset(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{5*3600 + 1800, "+0530|+05:30|+05:30:00"},
+		{-(3*3600 + 1800), "-0330|-03:30|-03:30:00"},
+		{0, "+0000|+00:00|+00:00:00"},
+		{-(7*3600 + 30*60 + 15), "-073015|-07:30|-07:30:15"},
+		{-(17*60 + 30), "-001730|-00:17|-00:17:30"},
+	}
+	for _, test := range tests {
+		dt := time.Date(2019, 1, 2, 15, 4, 5, 0, time.FixedZone("", test.offset))
+		s, err := Format("%z|%:z|%::z", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	for format, value := range map[string]string{
+		"%Y-%m-%dT%H:%M:%S%:z":  "2019-01-02T15:04:05+05:30",
+		"%Y-%m-%dT%H:%M:%S%::z": "2019-01-02T15:04:05+05:30:00",
+	} {
+		dt, err := Parse(format, value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if _, offset := dt.Zone(); offset != 5*3600+1800 {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", format, offset, 5*3600+1800)
+		}
+	}
+
+	if _, err := Parse("%:z", "+0530"); err == nil {
+		t.Error("Parse: expected an error for an offset without a colon")
+	}
+}
+
+func TestOffsetSeconds(t *testing.T) {
+	tests := []struct {
+		value     string
+		offset    int
+		formatted string
+	}{
+		{"+0530", 5*3600 + 1800, "+0530"},
+		{"-001730", -(17*60 + 30), "-001730"},
+		{"+013015", 3600 + 30*60 + 15, "+013015"},
+		{"+013015.5", 3600 + 30*60 + 15, "+013015"},
+		{"-013015.000001", -(3600 + 30*60 + 15), "-013015"},
+		{"+000000", 0, "+0000"},
+	}
+	for _, test := range tests {
+		dt, err := Parse("%H:%M %z", "12:00 "+test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if _, offset := dt.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", test.value, offset, test.offset)
+		}
+		s, err := Format("%z", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.formatted {
+			t.Errorf("Given: %v, expected: %v", s, test.formatted)
+		}
+	}
+
+	// A fraction without digits is not part of the offset.
+	for _, value := range []string{"+01301", "+013061", "+0130."} {
+		if _, err := Parse("%z", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestParseOffsetZ(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		offset int
+	}{
+		{"%Y-%m-%dT%H:%M:%S%z", "2021-01-01T00:00:00Z", 0},
+		{"%Y-%m-%dT%H:%M:%S%z", "2021-01-01T00:00:00+0200", 2 * 3600},
+		{"%Y-%m-%dT%H:%M:%S%:z", "2021-01-01T00:00:00Z", 0},
+		{"%Y-%m-%dT%H:%M:%S%:z", "2021-01-01T00:00:00-05:00", -5 * 3600},
+		{"%Y-%m-%dT%H:%M:%S%::z", "2021-01-01T00:00:00Z", 0},
+	}
+	for _, test := range tests {
+		dt, err := Parse(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		expected := time.Date(2021, 1, 1, 0, 0, 0, 0, time.FixedZone("", test.offset))
+		if !dt.Equal(expected) {
+			t.Errorf("Parse(%q): given: %v, expected: %v", test.value, dt, expected)
+		}
+		if _, offset := dt.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", test.value, offset, test.offset)
+		}
+	}
+
+	dt, err := Parse("%H:%M%z", "12:00Z")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Location() != time.UTC {
+		t.Errorf("Given: %v, expected: %v", dt.Location(), time.UTC)
+	}
+}
+
+func TestModifiers(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%Oy|%Ey|%y", "21|21|21"},
+		{"%EY-%Om-%Od %OH:%OM:%OS", "2021-03-04 05:06:07"},
+		{"%Ec", "Thu Mar 04 05:06:07 2021"},
+		{"%-Od %_OH", "4  5"},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %q, expected: %q", s, test.expected)
+		}
+	}
+
+	dt_, err := Parse("%EY-%Om-%Od %OH:%OM:%OS", "2021-03-04 05:06:07")
+	if err != nil {
+		t.Error(err)
+	} else if dt_ != dt {
+		t.Errorf("Given: %v, expected: %v", dt_, dt)
+	}
+
+	if native, err := ToNative("%EY-%Om-%Od"); err != nil {
+		t.Error(err)
+	} else if native != "2006-01-02" {
+		t.Errorf("Given: %v, expected: %v", native, "2006-01-02")
+	}
+	for _, format := range []string{"%Ed", "%Oa", "%E", "%EO"} {
+		if err := Validate(format); err == nil {
+			t.Errorf("Validate(%q): expected an error", format)
+		}
+	}
+}
+
+// The E modified combined directives of glibc fall back to the plain ones,
+// as there are no alternative eras.
+func TestEraModifiers(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, plain := range []string{"%c", "%C", "%x", "%X", "%y", "%Y"} {
+		era := "%E" + plain[1:]
+		s, err := Format(era, dt)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if expected, _ := Format(plain, dt); s != expected {
+			t.Errorf("Format(%q): given: %q, expected: %q", era, s, expected)
+		}
+
+		parsed, err := Parse(era, s)
+		if expected, expectedErr := Parse(plain, s); err != nil || expectedErr != nil {
+			t.Errorf("Parse(%q, %q): given error: %v, expected error: %v", era, s, err, expectedErr)
+		} else if parsed != expected {
+			t.Errorf("Parse(%q, %q): given: %v, expected: %v", era, s, parsed, expected)
+		}
+
+		native, err := ToNative(era)
+		if expected, expectedErr := ToNative(plain); (err == nil) != (expectedErr == nil) || native != expected {
+			t.Errorf("ToNative(%q): given: %q, %v, expected: %q, %v", era, native, err, expected, expectedErr)
+		}
+	}
+}
+
+func TestEpochSeconds(t *testing.T) {
+	dt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, err := Format("%s", dt.In(time.FixedZone("", -5*3600)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "1609459200" {
+		t.Errorf("Given: %v, expected: %v", s, "1609459200")
+	}
+
+	tests := []struct {
+		format, value string
+		expected      time.Time
+	}{
+		{"%s", "1609459200", dt},
+		{"%s", "-86400", time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"%s.%f", "1609459200.250", dt.Add(250 * time.Millisecond)},
+		// The zone only selects the location, not the instant.
+		{"%s %z", "1609459200 +0200", dt.In(time.FixedZone("", 2*3600))},
+		// Other date and time directives are ignored.
+		{"%Y-%m-%d %s", "1999-05-05 1609459200", dt},
+	}
+	for _, test := range tests {
+		parsed, err := Parse(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !parsed.Equal(test.expected) || parsed.String() != test.expected.String() {
+			t.Errorf("Given: %v, expected: %v", parsed, test.expected)
+		}
+	}
+}
+
+func TestEpochUnits(t *testing.T) {
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 891234567, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+		parsed   time.Time
+	}{
+		{"%Q", "1614834367891", dt.Truncate(time.Millisecond)},
+		{"%3s", "1614834367891", dt.Truncate(time.Millisecond)},
+		{"%6s", "1614834367891234", dt.Truncate(time.Microsecond)},
+		{"%9s", "1614834367891234567", dt},
+		{"%s", "1614834367", dt.Truncate(time.Second)},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt.In(time.FixedZone("", -5*3600)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Format(%q): given: %v, expected: %v", test.format, s, test.expected)
+		}
+		parsed, err := Parse(test.format, s)
+		if err != nil {
+			t.Error(err)
+		} else if parsed != test.parsed {
+			t.Errorf("Parse(%q): given: %v, expected: %v", test.format, parsed, test.parsed)
+		}
+	}
+
+	// Before the epoch the fraction still counts forward in time.
+	before := time.Date(1969, 12, 31, 23, 59, 59, 750000000, time.UTC)
+	if s, err := Format("%Q", before); err != nil || s != "-250" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "-250")
+	}
+	if parsed, err := Parse("%Q", "-250"); err != nil || parsed != before {
+		t.Errorf("Given: %v, %v, expected: %v", parsed, err, before)
+	}
+
+	if _, err := ToNative("%Q"); err == nil {
+		t.Error("ToNative: expected an error for %Q")
+	}
+	if err := Validate("%4s"); err == nil {
+		t.Error("Validate: expected an error for an unsupported epoch width")
+	}
+}
+
+func TestFixedFraction(t *testing.T) {
+	dt := time.Date(2019, 1, 2, 15, 4, 5, 120000000, time.UTC)
+	tests := []struct {
+		format, expected string
+	}{
+		{"%S.%L", "05.120"},
+		{"%S.%N", "05.120000000"},
+		{"%S,%L", "05,120"},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	parseTests := []struct {
+		format, value string
+		nsec          int
+		ok            bool
+	}{
+		{"%S.%L", "05.123", 123000000, true},
+		{"%S.%L", "05,123", 123000000, true},
+		{"%S.%L", "05.12", 0, false},
+		{"%S.%L", "05.1234", 0, false},
+		{"%S.%L", "05", 0, false},
+		{"%S.%N", "05.123456789", 123456789, true},
+		{"%S.%N", "05.123456", 0, false},
+	}
+	for _, test := range parseTests {
+		parsed, err := Parse(test.format, test.value)
+		if !test.ok {
+			if err == nil {
+				t.Errorf("Parse(%q, %q): expected an error", test.format, test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+		} else if parsed.Nanosecond() != test.nsec {
+			t.Errorf("Given: %v, expected: %v", parsed.Nanosecond(), test.nsec)
+		}
+	}
+}
+
+func TestFixedWidthMicroseconds(t *testing.T) {
+	dt := time.Date(2019, 1, 2, 15, 4, 5, 500000000, time.UTC)
+	tests := []struct {
+		format, expected string
+	}{
+		{"%S.%6f", "05.500000"},
+		{"%S.%f", "05.5"},
+	}
+	for _, test := range tests {
+		s, err := Format(test.format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	zero, err := Format("%S.%6f", dt.Truncate(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zero != "05.000000" {
+		t.Errorf("Given: %v, expected: %v", zero, "05.000000")
+	}
+
+	parsed, err := Parse("%S.%6f", "05.500000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Nanosecond() != 500000000 {
+		t.Errorf("Given: %v, expected: %v", parsed.Nanosecond(), 500000000)
+	}
+	if _, err := Parse("%S.%6f", "05.5"); err == nil {
+		t.Error("Parse: expected an error for a short fraction with %6f")
+	}
+}
+
+func TestFractionWidth(t *testing.T) {
+	dt := time.Date(2019, 1, 2, 15, 4, 5, 123456789, time.UTC)
+	expected := []string{"1", "12", "123", "1234", "12345", "123456", "1234567", "12345678", "123456789"}
+	for i, e := range expected {
+		format := "%S.%" + string(rune('1'+i)) + "f"
+		s, err := Format(format, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != "05."+e {
+			t.Errorf("Format(%q): given: %v, expected: %v", format, s, "05."+e)
+		}
+
+		parsed, err := Parse(format, s)
+		if err != nil {
+			t.Error(err)
+		} else if nsec := dt.Nanosecond() / pow10[8-i] * pow10[8-i]; parsed.Nanosecond() != nsec {
+			t.Errorf("Parse(%q): given: %v, expected: %v", format, parsed.Nanosecond(), nsec)
+		}
+	}
+
+	for _, format := range []string{"%S.%0f", "%S.%10f"} {
+		if _, err := Format(format, dt); err == nil {
+			t.Errorf("Format(%q): expected an error", format)
+		} else if expected := `unsupported width in directive "` + format[3:] + `" at offset 3`; err.Error() != expected {
+			t.Errorf("Given: %v, expected: %v", err, expected)
+		}
+	}
+}
+
+var pow10 = []int{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000}
+
+func TestCompile(t *testing.T) {
+	l, err := Compile(format1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := l.Format(dt1); s != value1 {
+		t.Errorf("Given: %v, expected: %v", s, value1)
+	}
+	if dt, err := l.Parse(value1); err != nil {
+		t.Error(err)
+	} else if dt != dt1 {
+		t.Errorf("Given: %v, expected: %v", dt, dt1)
+	}
+	if l.String() != format1 {
+		t.Errorf("Given: %v, expected: %v", l.String(), format1)
+	}
+
+	if _, err := Compile("%Y %v"); err == nil {
+		t.Error("Compile: expected an error for an unsupported directive")
+	}
+}
+
+func TestMustCompile(t *testing.T) {
+	if s := MustCompile(format1).Format(dt1); s != value1 {
+		t.Errorf("Given: %v, expected: %v", s, value1)
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("MustCompile: expected a panic for an unsupported directive")
+		} else if expected := `ctimefmt: Compile("%Y %v"): unsupported directive "%v" at offset 3`; r != expected {
+			t.Errorf("Given: %v, expected: %v", r, expected)
+		}
+	}()
+	MustCompile("%Y %v")
+}
+
+func BenchmarkFormat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Format(format1, dt1)
+	}
+}
+
+func BenchmarkAppendFormat(b *testing.B) {
+	b.ReportAllocs()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		buf, _ = AppendFormat(buf[:0], format1, dt1)
+	}
+}
+
+func BenchmarkLayoutFormat(b *testing.B) {
+	l, err := Compile(format1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Format(dt1)
+	}
+}
+
+func BenchmarkLayoutFormatTo(b *testing.B) {
+	l, err := Compile(format1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.FormatTo(ioutil.Discard, dt1)
+	}
+}
+
+func TestLenientOffset(t *testing.T) {
+	for _, value := range []string{
+		"2019-01-02T15:04:05+07:00",
+		"2019-01-02T15:04:05+0700",
+	} {
+		dt, err := Parse("%Y-%m-%dT%H:%M:%S%Ez", value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if _, offset := dt.Zone(); offset != 7*3600 {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", value, offset, 7*3600)
+		}
+	}
+
+	dt, err := Parse("%H:%M%Ez", "12:00Z")
+	if err != nil {
+		t.Error(err)
+	} else if dt.Location() != time.UTC {
+		t.Errorf("Given: %v, expected: %v", dt.Location(), time.UTC)
+	}
+
+	s, err := Format("%Ez", time.Date(2019, 1, 2, 15, 4, 5, 0, time.FixedZone("", -(3*3600+1800))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "-03:30" {
+		t.Errorf("Given: %v, expected: %v", s, "-03:30")
+	}
+
+	for _, value := range []string{"+07", "+07:0", "+070", "07:00"} {
+		if _, err := Parse("%Ez", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestParseISOWeekDate(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    string
+		expected time.Time
+	}{
+		{"%G-W%V-%u", "2021-W05-3", time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2021-W01-1", time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2020-W53-7", time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2020-W01-1", time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2015-W53-5", time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2009-W01-1", time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{"%GW%V%u", "2004W536", time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%g-W%V-%u", "21-W05-3", time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V %A %H:%M", "2021-W05 Sunday 10:20", time.Date(2021, 2, 7, 10, 20, 0, 0, time.UTC)},
+		// Without a weekday the date is the Monday of the week.
+		{"%G-W%V", "2021-W05", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// A Gregorian date takes precedence.
+		{"%Y-%m-%d %G-W%V", "2021-03-04 2021-W01", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := Parse(test.format, test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if dt != test.expected {
+			t.Errorf("Parse(%q): given: %v, expected: %v", test.value, dt, test.expected)
+		}
+		if s, err := Format(test.format, dt); err != nil {
+			t.Fatal(err)
+		} else if test.format[1] != 'Y' && s != test.value {
+			t.Errorf("Given: %v, expected: %v", s, test.value)
+		}
+	}
+
+	// 2021 has 52 weeks.
+	for _, value := range []string{"2021-W53-1", "2021-W00-1", "2021-W54-1", "2021-W05-8"} {
+		if _, err := Parse("%G-W%V-%u", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestISOWeekLabel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2021-W01", time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"2021-W05", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"2020-W53", time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC)},
+		{"2020-W01", time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dt, err := Parse("%G%:V", test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if dt != test.expected {
+			t.Errorf("Parse(%q): given: %v, expected: %v", test.value, dt, test.expected)
+		}
+		// Every day of the week has the same label.
+		for i := 0; i < 7; i++ {
+			if s, err := Format("%G%:V", dt.AddDate(0, 0, i)); err != nil || s != test.value {
+				t.Errorf("Given: %v, %v, expected: %v", s, err, test.value)
+			}
+		}
+	}
+
+	for _, value := range []string{"2021-W53", "2021W05", "2021-w05", "2021-W5"} {
+		if _, err := Parse("%G%:V", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestAdaptiveOffset(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{7 * 3600, "+07"},
+		{-5 * 3600, "-05"},
+		{0, "+00"},
+		{5*3600 + 1800, "+05:30"},
+		{-(9*3600 + 1800), "-09:30"},
+		{-(17*60 + 30), "-00:17:30"},
+	}
+	for _, test := range tests {
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", test.offset))
+		s, err := Format("%Y-%m-%d %H:%M:%S%:::z", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "2021-03-04 05:06:07" + test.expected; s != expected {
+			t.Errorf("Given: %v, expected: %v", s, expected)
+		}
+
+		parsed, err := ParseStrict("%Y-%m-%d %H:%M:%S%:::z", s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !parsed.Equal(dt) {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+		if _, offset := parsed.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", s, offset, test.offset)
+		}
+	}
+
+	// Any of the forms parses, whatever the offset.
+	for _, value := range []string{"+05:00", "+05:00:00", "Z"} {
+		if _, err := ParseStrict("%:::z", value); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, value := range []string{"05", "+5", "+05:3", "+0530"} {
+		if _, err := ParseStrict("%:::z", value); err == nil {
+			t.Errorf("ParseStrict(%q): expected an error", value)
+		}
+	}
+}
+
+func TestMinuteOffset(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{7 * 3600, "+420"},
+		{-4 * 3600, "-240"},
+		{5*3600 + 1800, "+330"},
+		{-(9*3600 + 1800), "-570"},
+		{0, "+0"},
+		{14 * 3600, "+840"},
+	}
+	for _, test := range tests {
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", test.offset))
+		s, err := Format("%Y-%m-%d %H:%M:%S %Oz", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "2021-03-04 05:06:07 " + test.expected; s != expected {
+			t.Errorf("Given: %v, expected: %v", s, expected)
+		}
+
+		parsed, err := Parse("%Y-%m-%d %H:%M:%S %Oz", s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !parsed.Equal(dt) {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+		if _, offset := parsed.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", s, offset, test.offset)
+		}
+	}
+
+	for _, value := range []string{"420", "+", "+1441", "-x"} {
+		if _, err := Parse("%Oz", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestOffsetHours(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{7 * 3600, "+07"},
+		{-5 * 3600, "-05"},
+		{0, "+00"},
+	}
+	for _, test := range tests {
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", test.offset))
+		s, err := Format("%Y-%m-%d %H:%M:%S %i", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "2021-03-04 05:06:07 " + test.expected; s != expected {
+			t.Errorf("Given: %v, expected: %v", s, expected)
+		}
+
+		parsed, err := Parse("%Y-%m-%d %H:%M:%S %i", s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !parsed.Equal(dt) {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+		if _, offset := parsed.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", s, offset, test.offset)
+		}
+	}
+
+	// Like Go's -07, the minutes of sub-hour offsets are dropped.
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", 5*3600+1800))
+	if s, err := Format("%i", dt); err != nil || s != "+05" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "+05")
+	}
+	for _, value := range []string{"07", "+7", "+0530", "+25"} {
+		if _, err := ParseStrict("%i", value); err == nil {
+			t.Errorf("ParseStrict(%q): expected an error", value)
+		}
+	}
+}
+
+func TestParseFlexibleYear(t *testing.T) {
+	expected := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2021-03-04", "21-03-04"} {
+		parsed, err := Parse("%:Y-%m-%d", value)
+		if err != nil {
+			t.Error(err)
+		} else if parsed != expected {
+			t.Errorf("Parse(%q): given: %v, expected: %v", value, parsed, expected)
+		}
+	}
+	if s, err := Format("%:Y-%m-%d", expected); err != nil || s != "2021-03-04" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "2021-03-04")
+	}
+
+	// Two-digit years are placed like %y, or by the pivot.
+	tests := []struct {
+		value    string
+		pivot    int
+		expected int
+	}{
+		{"69-03-04", -1, 1969},
+		{"68-03-04", -1, 2068},
+		{"1968-03-04", -1, 1968},
+		{"49-03-04", 50, 2049},
+		{"50-03-04", 50, 1950},
+		{"1949-03-04", 50, 1949},
+	}
+	for _, test := range tests {
+		var parsed time.Time
+		var err error
+		if test.pivot < 0 {
+			parsed, err = Parse("%:Y-%m-%d", test.value)
+		} else {
+			parsed, err = ParseWithPivot("%:Y-%m-%d", test.value, test.pivot)
+		}
+		if err != nil {
+			t.Error(err)
+		} else if parsed.Year() != test.expected {
+			t.Errorf("Parse(%q): given: %v, expected: %v", test.value, parsed.Year(), test.expected)
+		}
+	}
+
+	for _, value := range []string{"202-03-04", "2-03-04", "-03-04", "x"} {
+		if _, err := Parse("%:Y-%m-%d", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+// Years before 1 are astronomical, year 0 being 1 BC, like Go's 2006.
+func TestYearBounds(t *testing.T) {
+	tests := []struct {
+		year     int
+		expected string
+	}{
+		{1, "0001-03-04"},
+		{99, "0099-03-04"},
+		{9999, "9999-03-04"},
+		{0, "0000-03-04"},
+		{-1, "-0001-03-04"},
+		{-44, "-0044-03-04"},
+	}
+	for _, test := range tests {
+		dt := time.Date(test.year, 3, 4, 0, 0, 0, 0, time.UTC)
+		s, err := Format("%Y-%m-%d", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+		if native := dt.Format("2006-01-02"); s != native {
+			t.Errorf("Given: %v, expected: %v", s, native)
+		}
+		parsed, err := Parse("%Y-%m-%d", s)
+		if err != nil {
+			t.Error(err)
+		} else if !parsed.Equal(dt) {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+	}
+
+	// Years after 9999 render with all their digits, which Parse rejects.
+	dt := time.Date(10000, 3, 4, 0, 0, 0, 0, time.UTC)
+	if s, err := Format("%Y-%m-%d", dt); err != nil || s != "10000-03-04" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "10000-03-04")
+	}
+	for _, value := range []string{"10000-03-04", "-10000-03-04", "-03-04", "--001-03-04"} {
+		if _, err := Parse("%Y-%m-%d", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestZuluOffset(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{0, "2021-03-04T05:06:07Z 05:06:07Z"},
+		{5 * 3600, "2021-03-04T05:06:07+05:00 05:06:07+0500"},
+		{-(3*3600 + 1800), "2021-03-04T05:06:07-03:30 05:06:07-0330"},
+	}
+	for _, test := range tests {
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", test.offset))
+		s, err := Format("%Y-%m-%dT%H:%M:%S%#:z %H:%M:%S%#z", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+		if native := dt.Format(time.RFC3339 + " 15:04:05Z0700"); s != native {
+			t.Errorf("Given: %v, expected: %v", s, native)
+		}
+
+		parsed, err := Parse("%Y-%m-%dT%H:%M:%S%#:z", s[:strings.IndexByte(s, ' ')])
+		if err != nil {
+			t.Error(err)
+		} else if !parsed.Equal(dt) {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+	}
+}
+
+func TestParseUnpadded(t *testing.T) {
+	expected := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, format := range []string{"%Y-%-m-%-d %-H:%-M:%-S", "%Y-%_m-%_d %_H:%_M:%_S"} {
+		for _, value := range []string{"2021-3-4 5:6:7", "2021-03-04 05:06:07", "2021-3-04 05:6:07"} {
+			parsed, err := Parse(format, value)
+			if err != nil {
+				t.Error(err)
+			} else if parsed != expected {
+				t.Errorf("Given: %v, expected: %v", parsed, expected)
+			}
+		}
+	}
+
+	if _, err := Parse("%Y-%m-%d %H:%M:%S", "2021-3-4 5:6:7"); err == nil {
+		t.Errorf("Parse(%q): expected an error", "2021-3-4 5:6:7")
+	}
+	// %d and %I, like %H, accept one digit, except with ParseStrict.
+	parsed, err := Parse("%Y-%m-%d %I:%M:%S %p", "2021-03-4 5:06:07 AM")
+	if err != nil {
+		t.Error(err)
+	} else if parsed != expected {
+		t.Errorf("Given: %v, expected: %v", parsed, expected)
+	}
+	if _, err := ParseStrict("%Y-%m-%d %I:%M:%S %p", "2021-03-4 5:06:07 AM"); err == nil {
+		t.Errorf("ParseStrict(%q): expected an error", "2021-03-4 5:06:07 AM")
+	}
+	if _, err := Parse("%Y-%-m-%-d", "2021-003-4"); err == nil {
+		t.Errorf("Parse(%q): expected an error", "2021-003-4")
+	}
+}
+
+func TestParseFracSeparator(t *testing.T) {
+	expected := time.Date(0, 1, 1, 5, 6, 7, 123000000, time.UTC)
+	for _, format := range []string{"%H:%M:%S.%L", "%H:%M:%S,%L", "%H:%M:%S.%f", "%H:%M:%S,%3f"} {
+		for _, value := range []string{"05:06:07.123", "05:06:07,123"} {
+			parsed, err := Parse(format, value)
+			if err != nil {
+				t.Error(err)
+			} else if !parsed.Equal(expected) {
+				t.Errorf("Given: %v, expected: %v", parsed, expected)
+			}
+		}
+	}
+
+	// Without a separator in the format the fraction follows the seconds.
+	if _, err := Parse("%H:%M:%S%L", "05:06:07,123"); err == nil {
+		t.Errorf("Parse(%q): expected an error", "05:06:07,123")
+	}
+}
+
+func TestHourOffset(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{7 * 3600, "+7"},
+		{-5 * 3600, "-5"},
+		{0, "+0"},
+		{-11 * 3600, "-11"},
+		{14 * 3600, "+14"},
+	}
+	for _, test := range tests {
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", test.offset))
+		s, err := Format("%Y-%m-%d %H:%M:%S %-i", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "2021-03-04 05:06:07 " + test.expected; s != expected {
+			t.Errorf("Given: %v, expected: %v", s, expected)
+		}
+
+		parsed, err := Parse("%Y-%m-%d %H:%M:%S %-i", s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !parsed.Equal(dt) {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+		if _, offset := parsed.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", s, offset, test.offset)
+		}
+	}
+
+	// The minutes of sub-hour offsets are dropped.
+	for offset, expected := range map[int]string{5*3600 + 1800: "+5", -(9*3600 + 1800): "-9", -1800: "-0"} {
+		dt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", offset))
+		if s, err := Format("%-i", dt); err != nil || s != expected {
+			t.Errorf("Given: %v, %v, expected: %v", s, err, expected)
+		}
+	}
+
+	for _, value := range []string{"7", "+", "+25", "-x"} {
+		if _, err := Parse("%-i", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestPrefixedOffset(t *testing.T) {
+	tests := []struct {
+		value  string
+		offset int
+	}{
+		{"UTC+02:00", 2 * 3600},
+		{"GMT-0500", -5 * 3600},
+		{"UTC+2", 2 * 3600},
+		{"UTC-03:30", -(3*3600 + 1800)},
+		{"gmt+0530", 5*3600 + 1800},
+		{"+01:00", 3600},
+		{"UTC", 0},
+		{"GMT", 0},
+		{"Z", 0},
+	}
+	for _, test := range tests {
+		dt, err := Parse("%Y-%m-%d %H:%M:%S %EZ", "2021-03-04 05:06:07 "+test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if _, offset := dt.Zone(); offset != test.offset {
+			t.Errorf("Parse(%q): given offset: %v, expected: %v", test.value, offset, test.offset)
+		}
+		if expected := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", test.offset)); !dt.Equal(expected) {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+	}
+
+	// A bare UTC still lets the next directive follow.
+	if _, err := Parse("%EZ %H", "UTC 05"); err != nil {
+		t.Error(err)
+	}
+
+	for offset, expected := range map[int]string{0: "UTC", 2 * 3600: "UTC+02:00", -(3*3600 + 1800): "UTC-03:30"} {
+		s, err := Format("%EZ", time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", offset)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != expected {
+			t.Errorf("Given: %v, expected: %v", s, expected)
+		}
+	}
+
+	for _, value := range []string{"CET", "UTC+", "UTC+25", "UTC+02:6", "EST-0500"} {
+		if _, err := Parse("%EZ", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func BenchmarkLayoutParse(b *testing.B) {
+	l, err := Compile(format1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Parse(value1)
+	}
+}
+
+func TestLocationName(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2021, 3, 4, 5, 6, 7, 0, loc), "2021-03-04 05:06:07 America/New_York (EST)"},
+		{time.Date(2021, 7, 4, 5, 6, 7, 0, loc), "2021-07-04 05:06:07 America/New_York (EDT)"},
+		{time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), "2021-03-04 05:06:07 UTC (UTC)"},
+		{time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", 3600)), "2021-03-04 05:06:07 +0100 (+0100)"},
+	}
+	for _, test := range tests {
+		s, err := Format("%Y-%m-%d %H:%M:%S %:Z (%Z)", test.dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	// Parsing loads the location, so the zone rules apply.
+	for _, dt := range []time.Time{tests[0].dt, tests[1].dt, tests[2].dt} {
+		s, err := Format("%Y-%m-%d %H:%M:%S %:Z", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := Parse("%Y-%m-%d %H:%M:%S %:Z", s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !parsed.Equal(dt) || parsed.Location().String() != dt.Location().String() {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+	}
+
+	if _, err := Parse("%H:%M %:Z", "05:06 Nowhere/Atlantis"); err == nil {
+		t.Error("Parse: expected an error for an unknown location")
+	}
+}
+
+// Parse rejects fields out of their range instead of normalizing them
+// like time.Date does.
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		field  string
+	}{
+		{"%Y-%m-%d", "2021-13-01", "month"},
+		{"%Y-%m-%d", "2021-00-01", "month"},
+		{"%Y-%m-%d", "2021-01-32", "day"},
+		{"%Y-%m-%d", "2021-02-29", "day"},
+		{"%Y-%m-%d", "2021-04-31", "day"},
+		{"%Y-%m-%d", "2021-01-00", "day"},
+		{"%H:%M:%S", "24:00:00", "hour"},
+		{"%H:%M:%S", "25:00:00", "hour"},
+		{"%k:%M", "24:00", "hour"},
+		{"%-H:%M", "25:00", "hour"},
+		{"%I:%M %p", "13:00 PM", "hour"},
+		{"%H:%M:%S", "23:60:00", "minute"},
+		{"%H:%M:%S", "23:59:60", "second"},
+		{"%Y-%j", "2021-366", "day-of-year"},
+		{"%Y-%j", "2020-367", "day-of-year"},
+		{"%u", "8", "weekday"},
+		{"%V", "54", "week"},
+		{"%q", "5", "quarter"},
+		{"%z", "+2500", "time zone offset hour"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.format, test.value)
+		if err == nil {
+			t.Errorf("Parse(%q, %q): expected an error", test.format, test.value)
+		} else if expected := test.field + " out of range"; !strings.Contains(err.Error(), expected) {
+			t.Errorf("Parse(%q, %q): given: %v, expected: %v", test.format, test.value, err, expected)
+		}
+	}
+}
+
+// Hour 24, e.g. the 24:00:00 of ISO 8601 for the end of a day, is out of
+// range like any later hour.
+func TestDottedMeridiem(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{"03:04:05 p.m.", 15},
+		{"03:04:05 P.M.", 15},
+		{"03:04:05 a.m.", 3},
+		{"03:04:05 A.M.", 3},
+		{"12:04:05 a.m.", 0},
+		{"12:04:05 p.m.", 12},
+	}
+	for _, test := range tests {
+		for _, format := range []string{"%I:%M:%S %:p", "%I:%M:%S %:P"} {
+			dt, err := Parse(format, test.value)
+			if err != nil {
+				t.Error(err)
+			} else if dt.Hour() != test.expected {
+				t.Errorf("Parse(%q): given: %v, expected: %v", test.value, dt.Hour(), test.expected)
+			}
+		}
+	}
+
+	dt := time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC)
+	if s, err := Format("%I:%M %:p, %-I %:P", dt); err != nil || s != "03:04 P.M., 3 p.m." {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "03:04 P.M., 3 p.m.")
+	}
+	for _, value := range []string{"03:04:05 pm", "03:04:05 p.m", "03:04:05 x.m."} {
+		if _, err := Parse("%I:%M:%S %:p", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
+
+func TestParseHour24(t *testing.T) {
+	if dt, err := ParseStrict("%H:%M:%S", "23:59:59"); err != nil {
+		t.Error(err)
+	} else if dt.Hour() != 23 {
+		t.Errorf("Given: %v, expected: %v", dt.Hour(), 23)
+	}
+	for _, value := range []string{"24:00:00", "25:00:00", "99:00:00"} {
+		if _, err := ParseStrict("%H:%M:%S", value); err == nil || !strings.Contains(err.Error(), "hour out of range") {
+			t.Errorf("ParseStrict(%q): given: %v, expected: hour out of range", value, err)
+		}
+	}
+	if _, err := Parse("%H:%M:%S", "-1:00:00"); err == nil {
+		t.Errorf("Parse(%q): expected an error", "-1:00:00")
+	}
+}
+
+func TestWeekdayInitial(t *testing.T) {
+	expected := []string{"S", "M", "T", "W", "T", "F", "S"}
+	for i, initial := range expected {
+		// 2021-03-07 is a Sunday.
+		dt := time.Date(2021, 3, 7+i, 0, 0, 0, 0, time.UTC)
+		s, err := Format("%1a %a", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := initial + " " + dt.Weekday().String()[:3]; s != e {
+			t.Errorf("Given: %v, expected: %v", s, e)
+		}
+	}
+
+	s, err := FormatLocale("%1a", time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC), German)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "M" {
+		t.Errorf("Given: %v, expected: %v", s, "M")
+	}
+
+	// The initial is consumed but does not select a day.
+	dt, err := Parse("%1a %Y-%m-%d", "T 2021-03-11")
+	if err != nil {
+		t.Error(err)
+	} else if expected := time.Date(2021, 3, 11, 0, 0, 0, 0, time.UTC); dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+	if _, err := Parse("%1a", "X"); err == nil {
+		t.Error("Parse: expected an error for a letter which is no weekday initial")
+	}
+}
+
+func TestOrdinalDay(t *testing.T) {
+	tests := map[int]string{
+		1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 10: "10th",
+		11: "11th", 12: "12th", 13: "13th", 14: "14th",
+		21: "21st", 22: "22nd", 23: "23rd", 24: "24th",
+		30: "30th", 31: "31st",
+	}
+	for day, expected := range tests {
+		dt := time.Date(2021, 3, day, 0, 0, 0, 0, time.UTC)
+		s, err := Format("%B %:d, %Y", dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := "March " + expected + ", 2021"; s != e {
+			t.Errorf("Given: %v, expected: %v", s, e)
+		}
+
+		parsed, err := Parse("%B %:d, %Y", s)
+		if err != nil {
+			t.Error(err)
+		} else if parsed != dt {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+	}
+
+	if dt, err := Parse("%:d %b", "4TH Mar"); err != nil {
+		t.Error(err)
+	} else if dt.Day() != 4 {
+		t.Errorf("Given: %v, expected: %v", dt.Day(), 4)
+	}
+	for _, value := range []string{"4", "4st", "11st", "22th", "32nd"} {
+		if _, err := Parse("%:d", value); err == nil {
+			t.Errorf("Parse(%q): expected an error", value)
+		}
+	}
+}
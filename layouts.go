@@ -0,0 +1,37 @@
+package ctimefmt
+
+import "time"
+
+// Common formats in ctime-like syntax. The UTC offsets of RFC3339,
+// RFC3339Millis, ISO8601 and RFC5424 also parse Z for UTC. ISO8601Millis
+// is RFC3339Millis, the RFC 3339 profile of ISO 8601 with milliseconds,
+// whose offset unlike that of ISO8601 has a colon. RFC5424, the syslog
+// timestamp, has up to six fractional digits, which are omitted when
+// zero.
+const (
+	RFC3339       = "%Y-%m-%dT%H:%M:%S%:z"     // 2021-03-04T05:06:07+01:00
+	RFC3339Millis = "%Y-%m-%dT%H:%M:%S.%L%:z"  // 2021-03-04T05:06:07.890+01:00
+	ISO8601       = "%Y-%m-%dT%H:%M:%S%z"      // 2021-03-04T05:06:07+0100
+	ISO8601Millis = RFC3339Millis              // 2021-03-04T05:06:07.890+01:00
+	RFC5424       = "%Y-%m-%dT%H:%M:%S.%f%:z"  // 2021-03-04T05:06:07.123456+01:00
+	RFC1123       = "%a, %d %b %Y %H:%M:%S %Z" // Thu, 04 Mar 2021 05:06:07 CET
+	Syslog        = "%b %e %H:%M:%S"           // Mar  4 05:06:07
+	ApacheCLF     = "%d/%b/%Y:%H:%M:%S %z"     // 04/Mar/2021:05:06:07 +0100
+	UnixDate      = "%a %b %e %H:%M:%S %Z %Y"  // Thu Mar  4 05:06:07 CET 2021
+	DateTime      = "%Y-%m-%d %H:%M:%S"        // 2021-03-04 05:06:07
+)
+
+// iso8601Millis is RFC3339Millis rendering Z for a zero UTC offset.
+var iso8601Millis = MustCompile("%Y-%m-%dT%H:%M:%S.%L%#:z")
+
+// FormatISO8601Millis formats t like RFC3339Millis but with Z for a zero
+// UTC offset, as in 2021-01-01T00:00:00.000Z.
+func FormatISO8601Millis(t time.Time) string {
+	return iso8601Millis.Format(t)
+}
+
+// ParseISO8601Millis parses an RFC3339Millis timestamp, formatted by
+// FormatISO8601Millis or with the RFC3339Millis format.
+func ParseISO8601Millis(value string) (time.Time, error) {
+	return iso8601Millis.Parse(value)
+}
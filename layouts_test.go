@@ -0,0 +1,113 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestISO8601Millis(t *testing.T) {
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "2021-01-01T00:00:00.000Z"},
+		{time.Date(2021, 1, 1, 12, 30, 5, 123456789, time.UTC), "2021-01-01T12:30:05.123Z"},
+		{time.Date(2021, 6, 30, 23, 59, 59, 999000000, time.FixedZone("", 5*3600+1800)), "2021-06-30T23:59:59.999+05:30"},
+		{time.Date(2021, 6, 30, 0, 0, 0, 0, time.FixedZone("GMT", 0)), "2021-06-30T00:00:00.000Z"},
+	}
+	for _, test := range tests {
+		s := FormatISO8601Millis(test.dt)
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+
+		dt, err := ParseISO8601Millis(s)
+		if err != nil {
+			t.Error(err)
+		} else if expected := test.dt.Truncate(time.Millisecond); !dt.Equal(expected) {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+
+		if dt, err := Parse(ISO8601Millis, s); err != nil {
+			t.Error(err)
+		} else if !dt.Equal(test.dt.Truncate(time.Millisecond)) {
+			t.Errorf("Given: %v, expected: %v", dt, test.dt)
+		}
+	}
+
+	if s, err := Format(ISO8601Millis, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Error(err)
+	} else if expected := "2021-01-01T00:00:00.000+00:00"; s != expected {
+		t.Errorf("Given: %v, expected: %v", s, expected)
+	}
+	if _, err := ParseISO8601Millis("2021-01-01T00:00:00Z"); err == nil {
+		t.Error("ParseISO8601Millis: expected an error for a timestamp without milliseconds")
+	}
+}
+
+func TestNamedFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		sample string
+	}{
+		{RFC3339, "2021-03-04T05:06:07+01:00"},
+		{ISO8601, "2021-03-04T05:06:07-0700"},
+		{RFC3339Millis, "2021-03-04T05:06:07.890+01:00"},
+		{ISO8601Millis, "2021-03-04T05:06:07.890+01:00"},
+		{RFC5424, "2021-03-04T05:06:07.123456+01:00"},
+		{RFC5424, "2021-03-04T05:06:07+01:00"},
+		{RFC1123, "Thu, 04 Mar 2021 05:06:07 UTC"},
+		{Syslog, "Mar  4 05:06:07"},
+		{ApacheCLF, "04/Mar/2021:05:06:07 +0100"},
+		{UnixDate, "Thu Mar  4 05:06:07 UTC 2021"},
+		{DateTime, "2021-03-04 05:06:07"},
+	}
+	for _, test := range tests {
+		if err := Validate(test.format); err != nil {
+			t.Errorf("Validate(%q): %v", test.format, err)
+			continue
+		}
+		dt, err := Parse(test.format, test.sample)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s, err := Format(test.format, dt); err != nil {
+			t.Error(err)
+		} else if s != test.sample {
+			t.Errorf("Given: %v, expected: %v", s, test.sample)
+		}
+	}
+}
+
+func TestRFC5424(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+		format   string
+	}{
+		{"2003-10-11T22:14:15.003Z", time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC), "2003-10-11T22:14:15.003+00:00"},
+		{"2021-03-04T05:06:07.123456+05:30", time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.FixedZone("", 5*3600+1800)), ""},
+		{"2003-08-24T05:14:15.000003-07:00", time.Date(2003, 8, 24, 5, 14, 15, 3000, time.FixedZone("", -7*3600)), ""},
+		{"2021-03-04T05:06:07-09:30", time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", -(9*3600+1800))), ""},
+	}
+	for _, test := range tests {
+		dt, err := Parse(RFC5424, test.value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !dt.Equal(test.expected) {
+			t.Errorf("Given: %v, expected: %v", dt, test.expected)
+		}
+		expected := test.format
+		if expected == "" {
+			expected = test.value
+		}
+		if s, err := Format(RFC5424, dt); err != nil {
+			t.Error(err)
+		} else if s != expected {
+			t.Errorf("Given: %v, expected: %v", s, expected)
+		}
+	}
+}
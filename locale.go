@@ -0,0 +1,128 @@
+package ctimefmt
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// Locale holds the month and weekday names which %b, %B, %a and %A (and
+// the composite directives using them) render and parse. Weekdays start
+// with Sunday. Any language can be supplied by filling a Locale.
+type Locale struct {
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string
+	ShortDays   [7]string
+}
+
+// Built-in locales.
+var (
+	English = Locale{
+		Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	}
+	German = Locale{
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	}
+	French = Locale{
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	}
+	Spanish = Locale{
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	}
+)
+
+// directives returns the name directives of the locale.
+func (loc *Locale) directives() map[string]*directive {
+	shortMonth := monthName(loc.ShortMonths)
+	return map[string]*directive{
+		"%b":  shortMonth,
+		"%h":  shortMonth,
+		"%B":  monthName(loc.Months),
+		"%a":  weekdayName(loc.ShortDays),
+		"%A":  weekdayName(loc.Days),
+		"%1a": weekdayInitial(loc.Days),
+	}
+}
+
+func monthName(names [12]string) *directive {
+	return &directive{
+		format: func(b []byte, t time.Time) []byte { return append(b, names[t.Month()-1]...) },
+		parse:  parseMonthName(names[:]),
+	}
+}
+
+func weekdayName(names [7]string) *directive {
+	return &directive{
+		format: func(b []byte, t time.Time) []byte { return append(b, names[t.Weekday()]...) },
+		parse:  parseWeekdayName(names[:]),
+	}
+}
+
+// weekdayInitial renders the first letter of the weekday name. As
+// weekdays share initials, e.g. Tuesday and Thursday, parsing accepts any
+// initial and does not contribute to the parsed time.
+func weekdayInitial(names [7]string) *directive {
+	var initials [7]string
+	for i, name := range names {
+		_, n := utf8.DecodeRuneInString(name)
+		initials[i] = name[:n]
+	}
+	return &directive{
+		format: func(b []byte, t time.Time) []byte { return append(b, initials[t.Weekday()]...) },
+		parse: func(value string, f *fields) (string, error) {
+			_, rest, err := lookup(initials[:], value)
+			return rest, err
+		},
+	}
+}
+
+// WithLocale returns a copy of the layout which renders and parses month
+// and weekday names in the given locale.
+func (l *Layout) WithLocale(loc Locale) *Layout {
+	names := loc.directives()
+	c := &Layout{format: l.format, chunks: make([]chunk, len(l.chunks)), lenient: l.lenient, fast: l.fast}
+	copy(c.chunks, l.chunks)
+	for i, ch := range c.chunks {
+		if ch.d == nil || len(ch.text) < 2 {
+			continue
+		}
+		if d, ok := names[ch.text]; ok {
+			c.chunks[i].d = d
+		} else if d, ok := names["%"+ch.text[2:]]; ok && ch.text[1] == '^' {
+			c.chunks[i].d = upper(d)
+		}
+	}
+	return c
+}
+
+// FormatLocale is like Format but renders month and weekday names in the
+// given locale.
+func FormatLocale(format string, t time.Time, loc Locale) (string, error) {
+	l, err := compile(format)
+	if err != nil {
+		return "", err
+	}
+	return l.WithLocale(loc).Format(t), nil
+}
+
+// ParseLocale is like Parse but accepts month and weekday names in the
+// given locale.
+func ParseLocale(format, value string, loc Locale) (time.Time, error) {
+	l, err := compile(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.WithLocale(loc).Parse(value)
+}
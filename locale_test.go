@@ -0,0 +1,118 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatLocale(t *testing.T) {
+	dt := time.Date(2021, time.March, 3, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		loc      Locale
+		expected string
+	}{
+		{English, "Wednesday, 03 March 2021 (Wed Mar)"},
+		{German, "Mittwoch, 03 März 2021 (Mi Mär)"},
+		{French, "mercredi, 03 mars 2021 (mer. mars)"},
+		{Spanish, "miércoles, 03 marzo 2021 (mié mar)"},
+	}
+	for _, test := range tests {
+		s, err := FormatLocale("%A, %d %B %Y (%a %b)", dt, test.loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("Given: %v, expected: %v", s, test.expected)
+		}
+	}
+
+	s, err := FormatLocale("%^B %c", dt, German)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "MÄRZ Mi Mär 03 15:04:05 2021"; s != expected {
+		t.Errorf("Given: %v, expected: %v", s, expected)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	expected := time.Date(2021, time.March, 3, 0, 0, 0, 0, time.UTC)
+	dt, err := ParseLocale("%A, %d. %B %Y", "Mittwoch, 03. März 2021", German)
+	if err != nil {
+		t.Error(err)
+	} else if dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	dt, err = ParseLocale("%d %b %Y", "03 févr. 2021", French)
+	if err != nil {
+		t.Error(err)
+	} else if expected := time.Date(2021, time.February, 3, 0, 0, 0, 0, time.UTC); dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	if _, err := ParseLocale("%d %B %Y", "03 March 2021", German); err == nil {
+		t.Error("ParseLocale: expected an error for an English month name")
+	}
+
+	// A lenient layout stays lenient.
+	dt, err = CompileLenient("%d %B %Y um %H").WithLocale(German).Parse("03 März 2021 UM 00")
+	if err != nil {
+		t.Error(err)
+	} else if dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	// A custom locale.
+	dutch := English
+	dutch.Months = [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"}
+	dt, err = ParseLocale("%d %B %Y", "03 maart 2021", dutch)
+	if err != nil {
+		t.Error(err)
+	} else if dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+}
+
+// %h is an alias of %b, also in other locales.
+func TestShortMonthAlias(t *testing.T) {
+	dt := time.Date(2021, time.March, 3, 0, 0, 0, 0, time.UTC)
+	for _, loc := range []Locale{English, German, French, Spanish} {
+		b, err := FormatLocale("%b %^b %d %Y", dt, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := FormatLocale("%h %^h %d %Y", dt, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h != b {
+			t.Errorf("Given: %v, expected: %v", h, b)
+		}
+
+		s, err := FormatLocale("%b %d %Y", dt, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseLocale("%h %d %Y", s, loc)
+		if err != nil {
+			t.Error(err)
+		} else if parsed != dt {
+			t.Errorf("Given: %v, expected: %v", parsed, dt)
+		}
+	}
+
+	if s, err := Format("%h", dt); err != nil || s != "Mar" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "Mar")
+	}
+	for _, value := range []string{"Mar 03", "mar 03", "MAR 03"} {
+		b, errB := Parse("%b %d", value)
+		h, errH := Parse("%h %d", value)
+		if errB != nil || errH != nil || h != b {
+			t.Errorf("Parse(%q): given: %v, %v, expected: %v, %v", value, h, errH, b, errB)
+		}
+	}
+	if native, err := ToNative("%h"); err != nil || native != "Jan" {
+		t.Errorf("Given: %v, %v, expected: %v", native, err, "Jan")
+	}
+}
@@ -0,0 +1,162 @@
+package ctimefmt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Go layout element -> ctime format conversion, the inverse of
+// ctimeSubstitutes for the directives which map to a single element.
+// Fractional seconds are keyed by their digits, without separator.
+var nativeSubstitutes = reverseSubstitutes()
+
+func reverseSubstitutes() map[string]string {
+	// Go 1.20 added 002 for the day of the year. ToNative does not convert
+	// %j to it, so that its layouts mean the same to older Go versions.
+	m := map[string]string{"002": "%j"}
+	for directive, native := range ctimeSubstitutes {
+		if d := ctimeDirectives[directive]; d == nil || d.frac == 0 {
+			if prefix, std, suffix := nextStdChunk(native); prefix != "" || std != native || suffix != "" {
+				continue
+			}
+		}
+		// Prefer the shortest directive among aliases, e.g. %b over %h
+		// and %L over %3f.
+		if other, ok := m[native]; ok && (len(other) < len(directive) || len(other) == len(directive) && other < directive) {
+			continue
+		}
+		m[native] = directive
+	}
+	return m
+}
+
+// FromNative converts a Go native layout (which is used by
+// time.Time.Format() and time.Parse() functions) to a ctime-like format,
+// the inverse of ToNative.
+//
+// An error is returned if the layout contains an element which has no
+// ctime equivalent, e.g. __2 or fractional seconds of more than nine
+// digits. The error names the element and its byte offset in the layout.
+// Trimmed fractional seconds convert to the - flag of %f, e.g. the
+// .999999999 of time.RFC3339Nano to .%-9f, and the 002 day of the year of
+// Go 1.20 converts to %j.
+func FromNative(layout string) (string, error) {
+	var b strings.Builder
+	offset := 0
+	for layout != "" {
+		prefix, std, suffix := nextStdChunk(layout)
+		b.WriteString(strings.Replace(prefix, "%", "%%", -1))
+		if std == "" {
+			break
+		}
+
+		key := std
+		if isFracStd(std) {
+			b.WriteByte(std[0])
+			key = std[1:]
+		}
+		directive, ok := nativeSubstitutes[key]
+		if !ok {
+			return "", fmt.Errorf("layout element %q at offset %d has no ctime equivalent", std, offset+len(prefix))
+		}
+		b.WriteString(directive)
+
+		offset += len(layout) - len(suffix)
+		layout = suffix
+	}
+	return b.String(), nil
+}
+
+// nextStdChunk finds the first Go layout element in layout, following the
+// rules of the time package. It returns the literal text before it, the
+// element itself and the rest of layout. std is empty if layout has no
+// element.
+func nextStdChunk(layout string) (prefix, std, suffix string) {
+	hasPrefix := func(i int, s string) bool {
+		return strings.HasPrefix(layout[i:], s)
+	}
+	for i := 0; i < len(layout); i++ {
+		var n int
+		switch layout[i] {
+		case 'J': // January, Jan
+			if hasPrefix(i, "January") {
+				n = 7
+			} else if hasPrefix(i, "Jan") && !startsWithLowerCase(layout[i+3:]) {
+				n = 3
+			}
+		case 'M': // Monday, Mon, MST
+			if hasPrefix(i, "Monday") {
+				n = 6
+			} else if hasPrefix(i, "Mon") && !startsWithLowerCase(layout[i+3:]) || hasPrefix(i, "MST") {
+				n = 3
+			}
+		case '0': // 01, 02, 03, 04, 05, 06, 002
+			if i+1 < len(layout) && '1' <= layout[i+1] && layout[i+1] <= '6' {
+				n = 2
+			} else if hasPrefix(i, "002") {
+				n = 3
+			}
+		case '1': // 15, 1
+			if hasPrefix(i, "15") {
+				n = 2
+			} else {
+				n = 1
+			}
+		case '2': // 2006, 2
+			if hasPrefix(i, "2006") {
+				n = 4
+			} else {
+				n = 1
+			}
+		case '_': // _2, _2006, __2
+			if hasPrefix(i, "_2006") {
+				// A literal _ followed by 2006.
+				return layout[:i+1], "2006", layout[i+5:]
+			} else if hasPrefix(i, "_2") {
+				n = 2
+			} else if hasPrefix(i, "__2") {
+				n = 3
+			}
+		case '3', '4', '5':
+			n = 1
+		case 'P': // PM
+			if hasPrefix(i, "PM") {
+				n = 2
+			}
+		case 'p': // pm
+			if hasPrefix(i, "pm") {
+				n = 2
+			}
+		case '-', 'Z': // -070000, -07:00:00, -0700, -07:00, -07 and their Z forms
+			for _, tz := range []string{"070000", "07:00:00", "0700", "07:00", "07"} {
+				if hasPrefix(i+1, tz) {
+					n = 1 + len(tz)
+					break
+				}
+			}
+		case '.', ',': // .000 or .999, with any number of digits, or , instead of .
+			if i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+				j := i + 1
+				for j < len(layout) && layout[j] == layout[i+1] {
+					j++
+				}
+				// Only the same repeated digit makes fractional seconds.
+				if !isDigit(layout, j) {
+					n = j - i
+				}
+			}
+		}
+		if n > 0 {
+			return layout[:i], layout[i : i+n], layout[i+n:]
+		}
+	}
+	return layout, "", ""
+}
+
+func startsWithLowerCase(s string) bool {
+	return len(s) > 0 && 'a' <= s[0] && s[0] <= 'z'
+}
+
+func isFracStd(std string) bool {
+	return len(std) > 1 && (std[0] == '.' || std[0] == ',')
+}
@@ -0,0 +1,93 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromNative(t *testing.T) {
+	tests := []struct {
+		layout string
+		format string
+	}{
+		{"2006-01-02 15:04:05", "%Y-%m-%d %H:%M:%S"},
+		{time.ANSIC, "%a %b %e %H:%M:%S %Y"},
+		{time.UnixDate, "%a %b %e %H:%M:%S %Z %Y"},
+		{time.RubyDate, "%a %b %d %H:%M:%S %z %Y"},
+		{time.RFC822, "%d %b %y %H:%M %Z"},
+		{time.RFC822Z, "%d %b %y %H:%M %z"},
+		{time.RFC850, "%A, %d-%b-%y %H:%M:%S %Z"},
+		{time.RFC1123, "%a, %d %b %Y %H:%M:%S %Z"},
+		{time.RFC1123Z, "%a, %d %b %Y %H:%M:%S %z"},
+		{time.Kitchen, "%-I:%M%p"},
+		{time.Stamp, "%b %e %H:%M:%S"},
+		{time.StampMilli, "%b %e %H:%M:%S.%L"},
+		{time.StampMicro, "%b %e %H:%M:%S.%6f"},
+		{time.StampNano, "%b %e %H:%M:%S.%N"},
+		{"2006-01-02 15:04:05,999999 -07", "%Y-%m-%d %H:%M:%S,%f %i"},
+		{"January 2006, 03:04 PM", "%B %Y, %I:%M %p"},
+		{"Monday_2006 pm", "%A_%Y %P"},
+		{"2006 7 89%", "%Y 7 89%%"},
+		{time.RFC3339, "%Y-%m-%dT%H:%M:%S%#:z"},
+		{"20060102T150405Z0700", "%Y%m%dT%H%M%S%#z"},
+		{time.RFC3339Nano, "%Y-%m-%dT%H:%M:%S.%-9f%#:z"},
+		{"2006-01-02 15:04:05.999", "%Y-%m-%d %H:%M:%S.%-3f"},
+		{"15:04:05,99", "%H:%M:%S,%-2f"},
+		{"15:04:05.999999", "%H:%M:%S.%f"},
+	}
+	for _, test := range tests {
+		format, err := FromNative(test.layout)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if format != test.format {
+			t.Errorf("Given: %v, expected: %v", format, test.format)
+		}
+		native, err := ToNative(format)
+		if err != nil {
+			t.Error(err)
+		} else if native != test.layout {
+			t.Errorf("Given: %v, expected: %v", native, test.layout)
+		}
+	}
+}
+
+func TestFromNativeDayOfYear(t *testing.T) {
+	format, err := FromNative("2006-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "%Y-%j"; format != expected {
+		t.Errorf("Given: %v, expected: %v", format, expected)
+	}
+}
+
+func TestFromNativeLiterals(t *testing.T) {
+	format, err := FromNative("Jan Janet 15% off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "%b Janet %H%% off"; format != expected {
+		t.Errorf("Given: %v, expected: %v", format, expected)
+	}
+}
+
+func TestFromNativeErrors(t *testing.T) {
+	tests := []struct {
+		layout string
+		err    string
+	}{
+		{"2006 __2", `layout element "__2" at offset 5 has no ctime equivalent`},
+		{"15:04Z07", `layout element "Z07" at offset 5 has no ctime equivalent`},
+		{"2006-01-02 15:04:05.9999999999", `layout element ".9999999999" at offset 19 has no ctime equivalent`},
+	}
+	for _, test := range tests {
+		_, err := FromNative(test.layout)
+		if err == nil {
+			t.Errorf("Given: nil error, expected: %v", test.err)
+		} else if err.Error() != test.err {
+			t.Errorf("Given: %v, expected: %v", err, test.err)
+		}
+	}
+}
@@ -0,0 +1,1073 @@
+package ctimefmt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errBad is returned by directive parsers when the value does not match.
+var errBad = errors.New("bad value for field")
+
+// rangeError is returned by directive parsers when a field is out of range.
+type rangeError string
+
+func (e rangeError) Error() string {
+	return string(e) + " out of range"
+}
+
+var (
+	longMonthNames  = names(12, func(i int) string { return time.Month(i + 1).String() })
+	shortMonthNames = names(12, func(i int) string { return time.Month(i + 1).String()[:3] })
+	longDayNames    = names(7, func(i int) string { return time.Weekday(i).String() })
+	shortDayNames   = names(7, func(i int) string { return time.Weekday(i).String()[:3] })
+)
+
+func names(n int, name func(int) string) []string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = name(i)
+	}
+	return s
+}
+
+// fields holds the components of a time value being parsed. Unset
+// components are -1, mirroring the bookkeeping of time.Parse.
+type fields struct {
+	year, month, day, yday int
+	century, yy, quarter   int
+	weekday, otherWeekday  int // otherWeekday is an earlier, differing weekday
+	isoYear, isoWeek       int
+	yearSet                bool
+	hour, min, sec, nsec   int
+	pmSet, amSet, hour12   bool
+	leapSecond             bool // whether setSecond accepts 60
+
+	epochSet  bool
+	epoch     int64
+	epochNsec int64 // fraction of the epoch below a second
+
+	z          *time.Location
+	zoneOffset int
+	zoneName   string
+}
+
+// unsetFields are the fields before parsing.
+var unsetFields = fields{month: -1, day: -1, yday: -1, century: -1, yy: -1, quarter: -1, weekday: -1, otherWeekday: -1, isoYear: -1, isoWeek: -1, zoneOffset: -1}
+
+// fieldsPool recycles the fields of parses, which escape to the heap as
+// they are passed to the directive parsers.
+var fieldsPool = sync.Pool{
+	New: func() interface{} { return new(fields) },
+}
+
+func newFields() *fields {
+	f := fieldsPool.Get().(*fields)
+	*f = unsetFields
+	return f
+}
+
+func setYear(f *fields, v int) error {
+	f.year = v
+	f.yearSet = true
+	return nil
+}
+
+// setShortYear sets the year within the century, which a %C century
+// overrides when assembling the time.
+func setShortYear(f *fields, v int) error {
+	f.yy = v
+	if v >= 69 {
+		f.year = v + 1900
+	} else {
+		f.year = v + 2000
+	}
+	return nil
+}
+
+func setCentury(f *fields, v int) error {
+	f.century = v
+	return nil
+}
+
+func setMonth(f *fields, v int) error {
+	if v < 1 || v > 12 {
+		return rangeError("month")
+	}
+	f.month = v
+	return nil
+}
+
+// setQuarter sets the quarter of the year, which selects its first month
+// unless a month is parsed as well.
+func setQuarter(f *fields, v int) error {
+	if v < 1 || v > 4 {
+		return rangeError("quarter")
+	}
+	f.quarter = v
+	return nil
+}
+
+// setDay does not check the range of the day, which is validated against
+// the month and year once parsing has completed.
+func setDay(f *fields, v int) error {
+	f.day = v
+	return nil
+}
+
+func setYearDay(f *fields, v int) error {
+	if v < 1 || v > 366 {
+		return rangeError("day-of-year")
+	}
+	f.yday = v
+	return nil
+}
+
+// setISOYear sets the ISO 8601 week-based year, which with an ISO week
+// selects the date unless a month or day is parsed as well.
+func setISOYear(f *fields, v int) error {
+	f.isoYear = v
+	return nil
+}
+
+// setShortISOYear sets the week-based year within the century, which
+// like %y is in the 1900s from 69 and in the 2000s below.
+func setShortISOYear(f *fields, v int) error {
+	if v >= 69 {
+		f.isoYear = v + 1900
+	} else {
+		f.isoYear = v + 2000
+	}
+	return nil
+}
+
+// setISOWeek sets the ISO 8601 week number, see setISOYear.
+func setISOWeek(f *fields, v int) error {
+	if v < 1 || v > 53 {
+		return rangeError("week")
+	}
+	f.isoWeek = v
+	return nil
+}
+
+// setWeek accepts a %U or %W week number, which does not contribute to
+// the parsed time.
+func setWeek(f *fields, v int) error {
+	if v > 53 {
+		return rangeError("week")
+	}
+	return nil
+}
+
+// setWeekday accepts a %w weekday number, which like a weekday name does
+// not contribute to the parsed time but is checked by ParseChecked.
+func setWeekday(f *fields, v int) error {
+	if v > 6 {
+		return rangeError("weekday")
+	}
+	f.recordWeekday(v)
+	return nil
+}
+
+// setISOWeekday accepts a %u weekday number, which does not contribute to
+// the parsed time but is checked by ParseChecked.
+func setISOWeekday(f *fields, v int) error {
+	if v < 1 || v > 7 {
+		return rangeError("weekday")
+	}
+	f.recordWeekday(v % 7)
+	return nil
+}
+
+// recordWeekday records a weekday of 0 (Sunday) to 6, keeping a weekday
+// parsed before which it does not match, e.g. with both %A and %u in a
+// format, for ParseChecked to report.
+func (f *fields) recordWeekday(v int) {
+	if f.weekday >= 0 && f.weekday != v {
+		f.otherWeekday = f.weekday
+	}
+	f.weekday = v
+}
+
+func setHour(f *fields, v int) error {
+	if v > 23 {
+		return rangeError("hour")
+	}
+	f.hour = v
+	return nil
+}
+
+func setHour12(f *fields, v int) error {
+	if v > 12 {
+		return rangeError("hour")
+	}
+	f.hour = v
+	f.hour12 = true
+	return nil
+}
+
+func setMinute(f *fields, v int) error {
+	if v > 59 {
+		return rangeError("minute")
+	}
+	f.min = v
+	return nil
+}
+
+func setSecond(f *fields, v int) error {
+	if v > 59 && !(v == 60 && f.leapSecond) {
+		return rangeError("second")
+	}
+	f.sec = v
+	return nil
+}
+
+// parseOptions selects variants of parsing.
+type parseOptions struct {
+	// strict requires literal text to match exactly and disables the
+	// implicit fractional seconds after %S.
+	strict bool
+	// checkWeekday requires a parsed weekday to match the parsed date.
+	checkWeekday bool
+	// leapSecond accepts a second of 60, which rolls over to the next
+	// minute.
+	leapSecond bool
+	// pivot selects the century of a %y year by pivotYear instead of the
+	// fixed 69 of time.Parse.
+	pivot     bool
+	pivotYear int
+	// year is the year of a format without a year directive, if hasYear
+	// is set.
+	hasYear bool
+	year    int
+}
+
+// parse parses value according to the layout. Times without a zone are
+// returned in defaultLoc, zone names and offsets are resolved against
+// local, just like time.ParseInLocation does.
+func (l *Layout) parse(value string, defaultLoc, local *time.Location, opts parseOptions) (time.Time, error) {
+	f := newFields()
+	defer fieldsPool.Put(f)
+	f.leapSecond = opts.leapSecond
+	rest := value
+	for i, c := range l.chunks {
+		hold := rest
+		var err error
+		switch {
+		case c.d == nil && opts.strict:
+			if !strings.HasPrefix(rest, c.text) {
+				err = errBad
+			} else {
+				rest = rest[len(c.text):]
+			}
+		case c.d == nil:
+			rest, err = skip(rest, c.text, l.lenient)
+		case c.d.frac > 0:
+			rest, err = parseFrac(rest, c.d.frac, c.d.trim, c.sep, f)
+		case c.d.fold != nil && l.lenient && !opts.strict:
+			rest, err = c.d.fold(rest, f)
+		case c.d.unpadded && !opts.strict:
+			var n int
+			if n, rest, err = getnum(rest, c.d.width, '0', false); err == nil {
+				err = c.d.set(f, n)
+			}
+		default:
+			rest, err = c.d.parse(rest, f)
+			if err == nil && c.d.seconds && !opts.strict && !l.fracAfter(i) {
+				rest, err = parseFrac(rest, 9, true, '.', f)
+			}
+		}
+		if err != nil {
+			return time.Time{}, l.parseError(value, c.text, hold, err)
+		}
+	}
+	if rest != "" {
+		return time.Time{}, &time.ParseError{
+			Layout:    l.format,
+			Value:     value,
+			ValueElem: rest,
+			Message:   ": extra text: " + strconv.Quote(rest),
+		}
+	}
+
+	if opts.hasYear && !f.yearSet && f.yy < 0 && f.century < 0 && f.isoYear < 0 {
+		f.year = opts.year
+	}
+	if opts.pivot && f.yy >= 0 && f.century < 0 && !f.yearSet {
+		f.year = f.yy + 1900
+		if f.yy < opts.pivotYear {
+			f.year += 100
+		}
+	}
+
+	t, err := f.time(defaultLoc, local)
+	if err != nil {
+		return time.Time{}, l.parseError(value, "", "", err)
+	}
+	if opts.checkWeekday && f.otherWeekday >= 0 {
+		err := fmt.Errorf("weekday %v does not match weekday %v", time.Weekday(f.otherWeekday), time.Weekday(f.weekday))
+		return time.Time{}, l.parseError(value, "", "", err)
+	}
+	if opts.checkWeekday && f.weekday >= 0 && f.hasDate() && t.Weekday() != time.Weekday(f.weekday) {
+		err := fmt.Errorf("weekday %v does not match the date, which is a %v", time.Weekday(f.weekday), t.Weekday())
+		return time.Time{}, l.parseError(value, "", "", err)
+	}
+	return t, nil
+}
+
+func (l *Layout) parseError(value, elem, valueElem string, err error) error {
+	pe := &time.ParseError{
+		Layout:     l.format,
+		Value:      value,
+		LayoutElem: elem,
+		ValueElem:  valueElem,
+	}
+	if err != errBad {
+		pe.Message = ": " + err.Error()
+	}
+	return pe
+}
+
+// hasDate reports whether the fields determine the day, so that a
+// weekday can be checked against it.
+func (f *fields) hasDate() bool {
+	return f.epochSet || f.day >= 0 || f.yday >= 0
+}
+
+// isoDate returns the date of the ISO 8601 week date of the fields, the
+// Monday of the week unless a weekday is parsed as well.
+func (f *fields) isoDate() (time.Time, error) {
+	// Week 1 is the week of January 4th.
+	jan4 := time.Date(f.isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+	weekday := 1
+	if f.weekday >= 0 {
+		weekday = (f.weekday+6)%7 + 1
+	}
+	d := jan4.AddDate(0, 0, (f.isoWeek-1)*7+weekday-isoWeekday(jan4))
+	if year, week := d.ISOWeek(); year != f.isoYear || week != f.isoWeek {
+		return time.Time{}, rangeError("week")
+	}
+	return d, nil
+}
+
+// time assembles the parsed fields into a time value.
+func (f *fields) time(defaultLoc, local *time.Location) (time.Time, error) {
+	if f.epochSet {
+		return f.epochTime(defaultLoc, local), nil
+	}
+
+	if f.hour12 && !f.amSet && !f.pmSet {
+		return time.Time{}, errors.New("12-hour clock without AM/PM is ambiguous, %I and %l need %p or %P")
+	}
+
+	if f.century >= 0 {
+		// Without %y the year is the first one of the century.
+		f.year = f.century * 100
+		if f.yy >= 0 {
+			f.year += f.yy
+		}
+	}
+
+	hour := f.hour
+	if f.pmSet && hour < 12 {
+		hour += 12
+	} else if f.amSet && hour == 12 {
+		hour = 0
+	}
+
+	month, day := f.month, f.day
+	if f.isoYear >= 0 && f.isoWeek > 0 && month < 0 && day < 0 && f.yday < 0 {
+		d, err := f.isoDate()
+		if err != nil {
+			return time.Time{}, err
+		}
+		f.year, month, day = d.Year(), int(d.Month()), d.Day()
+	}
+	if f.yday >= 0 {
+		if f.yday > daysIn(13, f.year) {
+			return time.Time{}, rangeError("day-of-year")
+		}
+		d := time.Date(f.year, time.January, f.yday, 0, 0, 0, 0, time.UTC)
+		if month >= 0 && month != int(d.Month()) {
+			return time.Time{}, errors.New("day-of-year does not match month")
+		}
+		if day >= 0 && day != d.Day() {
+			return time.Time{}, errors.New("day-of-year does not match day")
+		}
+		month, day = int(d.Month()), d.Day()
+	} else {
+		if month < 0 && f.quarter > 0 {
+			month = (f.quarter-1)*3 + 1
+		}
+		if month < 0 {
+			month = int(time.January)
+		}
+		if day < 0 {
+			day = 1
+		}
+	}
+	if day < 1 || day > daysIn(month, f.year) {
+		return time.Time{}, rangeError("day")
+	}
+
+	if f.z != nil {
+		return time.Date(f.year, time.Month(month), day, hour, f.min, f.sec, f.nsec, f.z), nil
+	}
+
+	t := time.Date(f.year, time.Month(month), day, hour, f.min, f.sec, f.nsec, time.UTC)
+	if f.zoneOffset != -1 {
+		t = t.Add(-time.Duration(f.zoneOffset) * time.Second)
+		// Use the local zone if it has the given offset at that time.
+		lt := t.In(local)
+		if name, offset := lt.Zone(); offset == f.zoneOffset && (f.zoneName == "" || name == f.zoneName) {
+			return lt, nil
+		}
+		return t.In(time.FixedZone(f.zoneName, f.zoneOffset)), nil
+	}
+
+	if f.zoneName != "" {
+		if offset, ok := lookupZoneName(local, f.zoneName, t); ok {
+			return t.Add(-time.Duration(offset) * time.Second).In(local), nil
+		}
+		return t.In(unknownZone(f.zoneName)), nil
+	}
+
+	return time.Date(f.year, time.Month(month), day, hour, f.min, f.sec, f.nsec, defaultLoc), nil
+}
+
+// epochTime returns the time since the Unix epoch, in the location given
+// by the parsed zone if any and in defaultLoc otherwise.
+func (f *fields) epochTime(defaultLoc, local *time.Location) time.Time {
+	t := time.Unix(f.epoch, f.epochNsec+int64(f.nsec))
+	switch {
+	case f.z != nil:
+		return t.In(f.z)
+	case f.zoneOffset != -1:
+		lt := t.In(local)
+		if name, offset := lt.Zone(); offset == f.zoneOffset && (f.zoneName == "" || name == f.zoneName) {
+			return lt
+		}
+		return t.In(time.FixedZone(f.zoneName, f.zoneOffset))
+	case f.zoneName != "":
+		lt := t.In(local)
+		if name, _ := lt.Zone(); name == f.zoneName {
+			return lt
+		}
+		return t.In(unknownZone(f.zoneName))
+	}
+	return t.In(defaultLoc)
+}
+
+// unknownZone returns a zone recording a name which is not known to the
+// local zone. Its offset is unknown apart from GMT+h style names.
+func unknownZone(name string) *time.Location {
+	offset := 0
+	if len(name) > 3 && name[:3] == "GMT" {
+		offset, _ = atoi(name[3:])
+		offset *= 3600
+	}
+	return time.FixedZone(name, offset)
+}
+
+// daysIn returns the number of days in month of year. Month 13 stands
+// for the whole year.
+func daysIn(month int, year int) int {
+	if month == 13 {
+		return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// lookupZoneName returns the offset of the zone abbreviated name in loc,
+// preferring the zone in effect at the wall clock time t.
+func lookupZoneName(loc *time.Location, name string, t time.Time) (int, bool) {
+	var offsets []int
+	for _, probe := range []time.Time{
+		t,
+		time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(t.Year(), time.July, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if n, offset := probe.In(loc).Zone(); n == name {
+			offsets = append(offsets, offset)
+		}
+	}
+	for _, offset := range offsets {
+		if n, _ := t.Add(-time.Duration(offset) * time.Second).In(loc).Zone(); n == name {
+			return offset, true
+		}
+	}
+	if len(offsets) > 0 {
+		return offsets[0], true
+	}
+	return 0, false
+}
+
+// skip removes the literal prefix from value. As in time.Parse, a run of
+// spaces in prefix matches a non-empty run of spaces in value. With fold
+// set letters match regardless of case.
+func skip(value, prefix string, fold bool) (string, error) {
+	for len(prefix) > 0 {
+		if prefix[0] == ' ' {
+			if len(value) > 0 && value[0] != ' ' {
+				return value, errBad
+			}
+			prefix = cutspace(prefix)
+			value = cutspace(value)
+			continue
+		}
+		if len(value) == 0 || value[0] != prefix[0] && !(fold && match(value[:1], prefix[:1])) {
+			return value, errBad
+		}
+		prefix = prefix[1:]
+		value = value[1:]
+	}
+	return value, nil
+}
+
+func cutspace(s string) string {
+	for len(s) > 0 && s[0] == ' ' {
+		s = s[1:]
+	}
+	return s
+}
+
+func isDigit(s string, i int) bool {
+	if len(s) <= i {
+		return false
+	}
+	c := s[i]
+	return '0' <= c && c <= '9'
+}
+
+// atoi parses an optionally signed decimal number.
+func atoi(s string) (int, error) {
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, errBad
+	}
+	x := 0
+	for i := range s {
+		if !isDigit(s, i) {
+			return 0, errBad
+		}
+		x = x*10 + int(s[i]-'0')
+	}
+	if neg {
+		x = -x
+	}
+	return x, nil
+}
+
+// getnum reads up to width digits from value, skipping padding spaces
+// if pad is a space. Exactly width digits are required if fixed is set.
+func getnum(value string, width int, pad byte, fixed bool) (int, string, error) {
+	s := value
+	if pad == ' ' {
+		for i := 1; i < width && len(s) > 0 && s[0] == ' '; i++ {
+			s = s[1:]
+		}
+	}
+	n, i := 0, 0
+	for ; i < width && isDigit(s, i); i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	if i == 0 || fixed && i != width {
+		return 0, value, errBad
+	}
+	return n, s[i:], nil
+}
+
+func parseNumber(width int, pad byte, fixed bool, set func(*fields, int) error) func(string, *fields) (string, error) {
+	return func(value string, f *fields) (string, error) {
+		n, rest, err := getnum(value, width, pad, fixed)
+		if err != nil {
+			return value, err
+		}
+		return rest, set(f, n)
+	}
+}
+
+// parseYear parses a %Y year, four digits optionally preceded by a minus
+// sign for the years before 1.
+func parseYear(value string, f *fields) (string, error) {
+	s, neg := value, false
+	if len(s) > 0 && s[0] == '-' {
+		s, neg = s[1:], true
+	}
+	n, rest, err := getnum(s, 4, '0', true)
+	if err != nil {
+		return value, err
+	}
+	if neg {
+		n = -n
+	}
+	return rest, setYear(f, n)
+}
+
+// parseFlexibleYear parses a %:Y year, either a %Y year or a two-digit
+// %y year within the century.
+func parseFlexibleYear(value string, f *fields) (string, error) {
+	if value != "" && value[0] == '-' || isDigit(value, 2) {
+		return parseYear(value, f)
+	}
+	n, rest, err := getnum(value, 2, '0', true)
+	if err != nil {
+		return value, err
+	}
+	return rest, setShortYear(f, n)
+}
+
+// parseFrac parses the fractional seconds. With sep set the digits follow
+// a '.' or ','; with trim set the fraction is optional and may have any
+// number of digits.
+func parseFrac(value string, digits int, trim bool, sep byte, f *fields) (string, error) {
+	s := value
+	if sep != 0 {
+		if len(s) == 0 || s[0] != '.' && s[0] != ',' {
+			if trim {
+				return value, nil
+			}
+			return value, errBad
+		}
+		s = s[1:]
+	}
+	n := 0
+	for n < len(s) && (trim || n < digits) && isDigit(s, n) {
+		n++
+	}
+	if n == 0 && trim {
+		return value, nil
+	}
+	if n == 0 || !trim && n != digits {
+		return value, errBad
+	}
+	nsec := 0
+	for i := 0; i < 9; i++ {
+		nsec *= 10
+		if i < n {
+			nsec += int(s[i] - '0')
+		}
+	}
+	f.nsec = nsec
+	return s[n:], nil
+}
+
+// lookup returns the index of the name in tab which prefixes value,
+// ignoring ASCII case.
+func lookup(tab []string, value string) (int, string, error) {
+	for i, name := range tab {
+		if len(value) >= len(name) && match(value[:len(name)], name) {
+			return i, value[len(name):], nil
+		}
+	}
+	return -1, value, errBad
+}
+
+func match(s1, s2 string) bool {
+	for i := 0; i < len(s1); i++ {
+		c1, c2 := s1[i], s2[i]
+		if c1 != c2 {
+			c1 |= 'a' - 'A'
+			c2 |= 'a' - 'A'
+			if c1 != c2 || c1 < 'a' || c1 > 'z' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func parseMonthName(tab []string) func(string, *fields) (string, error) {
+	return func(value string, f *fields) (string, error) {
+		i, rest, err := lookup(tab, value)
+		if err != nil {
+			return value, err
+		}
+		f.month = i + 1
+		return rest, nil
+	}
+}
+
+// parseWeekdayName accepts a weekday name, which like in time.Parse does
+// not contribute to the parsed time but is checked by ParseChecked.
+func parseWeekdayName(tab []string) func(string, *fields) (string, error) {
+	return func(value string, f *fields) (string, error) {
+		i, rest, err := lookup(tab, value)
+		if err == nil {
+			f.recordWeekday(i)
+		}
+		return rest, err
+	}
+}
+
+// parseOrdinalDay parses a day of the month followed by its ordinal
+// suffix, e.g. 1st, 22nd or 13th, in any case.
+func parseOrdinalDay(value string, f *fields) (string, error) {
+	day, rest, err := getnum(value, 2, '0', false)
+	if err != nil {
+		return value, err
+	}
+	suffix := ordinalSuffix(day)
+	if len(rest) < 2 || !match(rest[:2], suffix) {
+		return value, errBad
+	}
+	return rest[2:], setDay(f, day)
+}
+
+func parseMeridiem(am, pm string) func(string, *fields) (string, error) {
+	return func(value string, f *fields) (string, error) {
+		if len(value) < 2 {
+			return value, errBad
+		}
+		switch value[:2] {
+		case am:
+			f.amSet = true
+		case pm:
+			f.pmSet = true
+		default:
+			return value, errBad
+		}
+		return value[2:], nil
+	}
+}
+
+// parseMeridiemFold parses a %p or %P meridiem, AM or PM in any case.
+func parseMeridiemFold(value string, f *fields) (string, error) {
+	if len(value) < 2 {
+		return value, errBad
+	}
+	switch {
+	case match(value[:2], "am"):
+		f.amSet = true
+	case match(value[:2], "pm"):
+		f.pmSet = true
+	default:
+		return value, errBad
+	}
+	return value[2:], nil
+}
+
+// parseDottedMeridiem parses a %:p or %:P meridiem, a.m. or p.m. in any
+// case.
+func parseDottedMeridiem(value string, f *fields) (string, error) {
+	if len(value) < 4 {
+		return value, errBad
+	}
+	switch {
+	case match(value[:4], "a.m."):
+		f.amSet = true
+	case match(value[:4], "p.m."):
+		f.pmSet = true
+	default:
+		return value, errBad
+	}
+	return value[4:], nil
+}
+
+// parseOffset parses a signed UTC offset made of parts two-digit numbers
+// (hours, minutes and seconds), separated by colons if colon is set. Like
+// Go's Z07:00 layouts it also accepts Z for UTC.
+func parseOffset(parts int, colon bool) func(string, *fields) (string, error) {
+	return func(value string, f *fields) (string, error) {
+		if len(value) > 0 && value[0] == 'Z' {
+			f.z = time.UTC
+			return value[1:], nil
+		}
+		if len(value) == 0 || value[0] != '+' && value[0] != '-' {
+			return value, errBad
+		}
+		s := value[1:]
+		var n [3]int
+		for i := 0; i < parts; i++ {
+			if i > 0 && colon {
+				if len(s) == 0 || s[0] != ':' {
+					return value, errBad
+				}
+				s = s[1:]
+			}
+			var err error
+			if n[i], s, err = getnum(s, 2, '0', true); err != nil {
+				return value, err
+			}
+		}
+		// Like time.Parse, allow offsets of 24 hours or 60 minutes.
+		switch {
+		case n[0] > 24:
+			return value, rangeError("time zone offset hour")
+		case n[1] > 60:
+			return value, rangeError("time zone offset minute")
+		case n[2] > 60:
+			return value, rangeError("time zone offset second")
+		}
+		f.zoneOffset = (n[0]*60+n[1])*60 + n[2]
+		if value[0] == '-' {
+			f.zoneOffset = -f.zoneOffset
+		}
+		return s, nil
+	}
+}
+
+// parseLenientOffset parses a %Ez offset, ±HH:MM or ±HHMM.
+func parseLenientOffset(value string, f *fields) (string, error) {
+	rest, err := parseOffset(2, true)(value, f)
+	if err != nil {
+		return parseOffset(2, false)(value, f)
+	}
+	return rest, nil
+}
+
+// parseAdaptiveOffset parses a %:::z offset, ±HH, ±HH:MM or ±HH:MM:SS.
+func parseAdaptiveOffset(value string, f *fields) (string, error) {
+	rest, err := parseOffset(3, true)(value, f)
+	if err != nil {
+		if rest, err = parseOffset(2, true)(value, f); err != nil {
+			return parseOffset(1, false)(value, f)
+		}
+	}
+	return rest, nil
+}
+
+// parseMinuteOffset parses a %Oz offset, a sign followed by up to four
+// digits of minutes.
+func parseMinuteOffset(value string, f *fields) (string, error) {
+	if len(value) > 0 && value[0] == 'Z' {
+		f.z = time.UTC
+		return value[1:], nil
+	}
+	if len(value) == 0 || value[0] != '+' && value[0] != '-' {
+		return value, errBad
+	}
+	n, rest, err := getnum(value[1:], 4, '0', false)
+	if err != nil {
+		return value, err
+	}
+	// Like time.Parse, allow offsets of up to 24 hours.
+	if n > 24*60 {
+		return value, rangeError("time zone offset minute")
+	}
+	f.zoneOffset = n * 60
+	if value[0] == '-' {
+		f.zoneOffset = -f.zoneOffset
+	}
+	return rest, nil
+}
+
+// parseHourOffset parses a %-i offset, a sign followed by one or two
+// digits of whole hours.
+func parseHourOffset(value string, f *fields) (string, error) {
+	if len(value) == 0 || value[0] != '+' && value[0] != '-' {
+		return value, errBad
+	}
+	n, rest, err := getnum(value[1:], 2, '0', false)
+	if err != nil {
+		return value, err
+	}
+	if n > 24 {
+		return value, rangeError("time zone offset hour")
+	}
+	f.zoneOffset = n * 3600
+	if value[0] == '-' {
+		f.zoneOffset = -f.zoneOffset
+	}
+	return rest, nil
+}
+
+// parsePrefixedOffset parses a %EZ offset, an optional UTC or GMT prefix
+// followed by ±HH[[:]MM] or ±H, e.g. UTC+02:00, GMT-0500 and UTC+2. Without
+// an offset the prefix is UTC.
+func parsePrefixedOffset(value string, f *fields) (string, error) {
+	s := value
+	if len(s) >= 3 && (match(s[:3], "UTC") || match(s[:3], "GMT")) {
+		s = s[3:]
+		if len(s) == 0 || s[0] != '+' && s[0] != '-' {
+			f.z = time.UTC
+			return s, nil
+		}
+	}
+	if len(s) > 0 && s[0] == 'Z' {
+		f.z = time.UTC
+		return s[1:], nil
+	}
+	if len(s) == 0 || s[0] != '+' && s[0] != '-' {
+		return value, errBad
+	}
+	hour, rest, err := getnum(s[1:], 2, '0', false)
+	if err != nil {
+		return value, err
+	}
+	min := 0
+	if rest != "" && rest[0] == ':' && isDigit(rest, 1) {
+		rest = rest[1:]
+	}
+	if isDigit(rest, 0) {
+		if min, rest, err = getnum(rest, 2, '0', true); err != nil {
+			return value, err
+		}
+	}
+	switch {
+	case hour > 24:
+		return value, rangeError("time zone offset hour")
+	case min > 60:
+		return value, rangeError("time zone offset minute")
+	}
+	f.zoneOffset = (hour*60 + min) * 60
+	if s[0] == '-' {
+		f.zoneOffset = -f.zoneOffset
+	}
+	return rest, nil
+}
+
+// parseFullOffset parses a %z offset, ±HHMM[SS[.ffffff]]. The fractional
+// part of the seconds is accepted for compatibility with other strptime
+// implementations, but dropped, as Go offsets are whole seconds.
+func parseFullOffset(value string, f *fields) (string, error) {
+	rest, err := parseOffset(2, false)(value, f)
+	if err != nil || value[0] == 'Z' || !isDigit(rest, 0) {
+		return rest, err
+	}
+	sec, rest, err := getnum(rest, 2, '0', true)
+	if err != nil {
+		return value, err
+	}
+	if sec > 60 {
+		return value, rangeError("time zone offset second")
+	}
+	if value[0] == '-' {
+		sec = -sec
+	}
+	f.zoneOffset += sec
+	if len(rest) > 1 && rest[0] == '.' && isDigit(rest, 1) {
+		n := 1
+		for isDigit(rest, n) {
+			n++
+		}
+		rest = rest[n:]
+	}
+	return rest, nil
+}
+
+// parseEpoch parses an optionally negative number of 1/perSecond seconds
+// since the Unix epoch.
+func parseEpoch(perSecond int64) func(string, *fields) (string, error) {
+	return func(value string, f *fields) (string, error) {
+		n := 0
+		if len(value) > 0 && value[0] == '-' {
+			n++
+		}
+		start := n
+		for isDigit(value, n) {
+			n++
+		}
+		if n == start {
+			return value, errBad
+		}
+		epoch, err := strconv.ParseInt(value[:n], 10, 64)
+		if err != nil {
+			return value, rangeError("epoch")
+		}
+		sec, frac := epoch/perSecond, epoch%perSecond
+		if frac < 0 {
+			sec, frac = sec-1, frac+perSecond
+		}
+		f.epoch, f.epochNsec, f.epochSet = sec, frac*(1e9/perSecond), true
+		return value[n:], nil
+	}
+}
+
+// parseZoneName parses a time zone abbreviation the way time.Parse does.
+func parseZoneName(value string, f *fields) (string, error) {
+	if len(value) >= 3 && value[:3] == "UTC" {
+		f.z = time.UTC
+		return value[3:], nil
+	}
+	n, ok := zoneNameLen(value)
+	if !ok {
+		return value, errBad
+	}
+	f.zoneName = value[:n]
+	return value[n:], nil
+}
+
+// parseLocationName parses a %:Z location name, e.g. America/New_York,
+// and loads the location with time.LoadLocation, which needs the time
+// zone database of the system or of the time/tzdata package.
+func parseLocationName(value string, f *fields) (string, error) {
+	n := 0
+	for n < len(value) && isLocationNameChar(value[n]) {
+		n++
+	}
+	if n == 0 {
+		return value, errBad
+	}
+	loc, err := time.LoadLocation(value[:n])
+	if err != nil {
+		return value, err
+	}
+	f.z = loc
+	return value[n:], nil
+}
+
+func isLocationNameChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '/' || c == '_' || c == '-' || c == '+'
+}
+
+// zoneNameLen returns the length of the time zone abbreviation at the
+// start of value: three to five upper-case letters, a signed hour offset,
+// or GMT optionally followed by one.
+func zoneNameLen(value string) (int, bool) {
+	if len(value) < 3 {
+		return 0, false
+	}
+	if len(value) >= 4 && (value[:4] == "ChST" || value[:4] == "MeST") {
+		return 4, true
+	}
+	if value[:3] == "GMT" {
+		return 3 + signedOffsetLen(value[3:]), true
+	}
+	if value[0] == '+' || value[0] == '-' {
+		n := signedOffsetLen(value)
+		return n, n > 0
+	}
+	var upper int
+	for upper < 6 && upper < len(value) && 'A' <= value[upper] && value[upper] <= 'Z' {
+		upper++
+	}
+	switch upper {
+	case 5:
+		if value[4] == 'T' {
+			return 5, true
+		}
+	case 4:
+		if value[3] == 'T' || value[:4] == "WITA" {
+			return 4, true
+		}
+	case 3:
+		return 3, true
+	}
+	return 0, false
+}
+
+// signedOffsetLen returns the length of a signed hour offset of at most
+// 24 hours at the start of value, or 0 if there is none.
+func signedOffsetLen(value string) int {
+	if len(value) == 0 || value[0] != '+' && value[0] != '-' {
+		return 0
+	}
+	n, x := 1, 0
+	for ; isDigit(value, n); n++ {
+		if x = x*10 + int(value[n]-'0'); x > 24 {
+			return 0
+		}
+	}
+	if n == 1 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+package ctimefmt
+
+import "time"
+
+// Parser parses timestamps of a single ctime-like format, e.g. the lines
+// of a log file. The format is converted once, when the Parser is
+// created, so that parsing many values does not repeat the work.
+//
+// A Parser is safe for concurrent use.
+type Parser struct {
+	layout *Layout
+}
+
+// NewParser returns a Parser for the format, or an error if it contains
+// an unsupported directive.
+func NewParser(format string) (*Parser, error) {
+	l, err := Compile(format)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{layout: l}, nil
+}
+
+// Parse is like the package-level Parse for the format of the Parser.
+func (p *Parser) Parse(value string) (time.Time, error) {
+	return p.layout.Parse(value)
+}
+
+// ParseInLocation is like the package-level ParseInLocation for the
+// format of the Parser. The location can differ from value to value, e.g.
+// for logs collected from hosts in several time zones.
+func (p *Parser) ParseInLocation(value string, loc *time.Location) (time.Time, error) {
+	return p.layout.parse(value, loc, loc, parseOptions{})
+}
+
+// String returns the ctime-like format of the Parser.
+func (p *Parser) String() string {
+	return p.layout.String()
+}
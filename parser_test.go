@@ -0,0 +1,75 @@
+package ctimefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParser(t *testing.T) {
+	p, err := NewParser("%Y-%m-%d %H:%M:%S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.String(); s != "%Y-%m-%d %H:%M:%S" {
+		t.Errorf("Given: %v, expected: %v", s, "%Y-%m-%d %H:%M:%S")
+	}
+
+	berlin := time.FixedZone("CET", 3600)
+	tokyo := time.FixedZone("JST", 9*3600)
+	tests := []struct {
+		value string
+		loc   *time.Location
+	}{
+		{"2021-03-04 15:04:05", time.UTC},
+		{"2021-03-04 15:04:05", berlin},
+		{"2021-03-04 15:04:05", tokyo},
+		{"2020-02-29 00:00:00", berlin},
+	}
+	for _, test := range tests {
+		dt, err := p.ParseInLocation(test.value, test.loc)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		expected, err := ParseInLocation("%Y-%m-%d %H:%M:%S", test.value, test.loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dt != expected {
+			t.Errorf("Given: %v, expected: %v", dt, expected)
+		}
+	}
+
+	dt, err := p.Parse(value1[:19])
+	if err != nil {
+		t.Error(err)
+	} else if expected := dt1.Truncate(time.Second); dt != expected {
+		t.Errorf("Given: %v, expected: %v", dt, expected)
+	}
+
+	if _, err := p.ParseInLocation("2021-03-04", time.UTC); err == nil {
+		t.Error("ParseInLocation: expected an error for a truncated value")
+	}
+	if _, err := NewParser("%Y-%v"); err == nil {
+		t.Error("NewParser: expected an error for an unsupported directive")
+	}
+}
+
+func BenchmarkParser(b *testing.B) {
+	p, err := NewParser(format1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.ParseInLocation(value1, time.UTC)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Parse(format1, value1)
+	}
+}
@@ -0,0 +1,140 @@
+package ctimefmt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var customRegexp = regexp.MustCompile(`^%[A-Za-z]$`)
+
+// errNoParser is returned when parsing a custom directive which has no
+// parser registered.
+var errNoParser = errors.New("no parser registered for directive")
+
+// customTable holds the directives added with RegisterDirective. Unlike
+// the built-in tables, which are never modified, it changes at run time,
+// so access to it is synchronized.
+type customTable struct {
+	mu         sync.RWMutex
+	directives map[string]*directive
+	parsers    map[string]func(value string) (int, error)
+}
+
+var customs = &customTable{
+	directives: make(map[string]*directive),
+	parsers:    make(map[string]func(value string) (int, error)),
+}
+
+func (c *customTable) lookup(name string) (*directive, bool) {
+	c.mu.RLock()
+	d, ok := c.directives[name]
+	c.mu.RUnlock()
+	return d, ok
+}
+
+func (c *customTable) parser(name string) func(value string) (int, error) {
+	c.mu.RLock()
+	parse := c.parsers[name]
+	c.mu.RUnlock()
+	return parse
+}
+
+// RegisterDirective adds a custom directive, a % followed by a single
+// letter, which Format renders with render. It returns an error if the
+// directive has another form or is already defined.
+//
+// The text of a custom directive does not contribute to a parsed time,
+// and Parse fails on it unless a parser is added with RegisterParser.
+//
+// Registration is safe for concurrent use with formatting and parsing,
+// but directives are best registered at init time so that every use of
+// a format sees them.
+func RegisterDirective(name string, render func(time.Time) string) error {
+	if !customRegexp.MatchString(name) {
+		return fmt.Errorf("invalid directive %q, expected %% followed by a letter", name)
+	}
+	if _, ok := modifiers[name[1]]; ok {
+		return fmt.Errorf("directive %q is reserved for a modifier", name)
+	}
+	if _, ok := ctimeDirectives[name]; ok {
+		return fmt.Errorf("directive %q is already defined", name)
+	}
+
+	customs.mu.Lock()
+	defer customs.mu.Unlock()
+	if _, ok := customs.directives[name]; ok {
+		return fmt.Errorf("directive %q is already defined", name)
+	}
+	customs.directives[name] = &directive{
+		format: func(b []byte, t time.Time) []byte { return append(b, render(t)...) },
+		parse: func(value string, f *fields) (string, error) {
+			// Look the parser up on use, as it may be registered after
+			// layouts using the directive were compiled.
+			parse := customs.parser(name)
+			if parse == nil {
+				return value, errNoParser
+			}
+			n, err := parse(value)
+			if err != nil {
+				return value, err
+			}
+			if n < 0 || n > len(value) {
+				return value, errBad
+			}
+			return value[n:], nil
+		},
+	}
+	return nil
+}
+
+// RegisterParser sets how Parse consumes the text of a directive added
+// with RegisterDirective. parse returns the length of the text matching
+// the directive at the start of value, or an error if it does not match.
+//
+// Like RegisterDirective, it is safe for concurrent use and best called
+// at init time.
+func RegisterParser(name string, parse func(value string) (int, error)) error {
+	customs.mu.Lock()
+	defer customs.mu.Unlock()
+	if _, ok := customs.directives[name]; !ok {
+		return fmt.Errorf("directive %q is not a custom directive", name)
+	}
+	customs.parsers[name] = parse
+	return nil
+}
+
+// zoneNames holds the function set with SetZoneNameFunc.
+var zoneNames struct {
+	mu   sync.RWMutex
+	name func(time.Time) string
+}
+
+// SetZoneNameFunc sets the function %Z renders the zone abbreviation of
+// a time with, e.g. to map zones the time zone database has no
+// abbreviation for, for which %Z renders the offset such as -0300. An
+// empty result falls back to the default, and so does passing nil.
+//
+// It only affects Format and the other formatting functions, not
+// ToNative. Like RegisterDirective it is safe for concurrent use, but is
+// best called once at init time.
+func SetZoneNameFunc(name func(t time.Time) string) {
+	zoneNames.mu.Lock()
+	zoneNames.name = name
+	zoneNames.mu.Unlock()
+}
+
+// appendZoneName appends the %Z zone abbreviation of t.
+func appendZoneName(b []byte, t time.Time) []byte {
+	zoneNames.mu.RLock()
+	name := zoneNames.name
+	zoneNames.mu.RUnlock()
+	if name != nil {
+		if s := name(t); s != "" {
+			return append(b, s...)
+		}
+	}
+	return t.AppendFormat(b, "MST")
+}
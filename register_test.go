@@ -0,0 +1,175 @@
+package ctimefmt
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fiscalWeek is the week of a fiscal year starting on the 1st of April.
+func fiscalWeek(t time.Time) string {
+	start := time.Date(t.Year(), time.April, 1, 0, 0, 0, 0, t.Location())
+	if t.Before(start) {
+		start = start.AddDate(-1, 0, 0)
+	}
+	return "FW" + strconv.Itoa(int(t.Sub(start).Hours())/(24*7)+1)
+}
+
+func parseFiscalWeek(value string) (int, error) {
+	n := 2
+	for n < len(value) && isDigit(value, n) {
+		n++
+	}
+	if n == 2 || value[:2] != "FW" {
+		return 0, errors.New("bad fiscal week")
+	}
+	return n, nil
+}
+
+// Directives are registered once per test binary, as they would be at
+// init time.
+var (
+	errRegisterK = RegisterDirective("%K", fiscalWeek)
+	errRegisterJ = RegisterDirective("%J", func(time.Time) string { return "J" })
+)
+
+func TestRegisterDirective(t *testing.T) {
+	if errRegisterK != nil {
+		t.Fatal(errRegisterK)
+	}
+	if err := RegisterParser("%K", parseFiscalWeek); err != nil {
+		t.Fatal(err)
+	}
+
+	dt := time.Date(2021, time.April, 20, 0, 0, 0, 0, time.UTC)
+	s, err := Format("%Y-%m-%d %K", dt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "2021-04-20 FW3"; s != expected {
+		t.Errorf("Given: %v, expected: %v", s, expected)
+	}
+
+	dt_, err := Parse("%Y-%m-%d %K", "2021-04-20 FW3")
+	if err != nil {
+		t.Error(err)
+	} else if dt_ != dt {
+		t.Errorf("Given: %v, expected: %v", dt_, dt)
+	}
+	if _, err := Parse("%Y-%m-%d %K", "2021-04-20 W3"); err == nil {
+		t.Error("Parse: expected an error for a mismatching custom directive")
+	}
+
+	if err := RegisterDirective("%K", fiscalWeek); err == nil {
+		t.Error("RegisterDirective: expected an error for a directive registered twice")
+	}
+}
+
+func TestRegisterDirectiveErrors(t *testing.T) {
+	for _, name := range []string{"%Y", "%d", "%n", "%%", "%E", "%O", "K", "%KK", "%1", "%-K", "%"} {
+		if err := RegisterDirective(name, fiscalWeek); err == nil {
+			t.Errorf("RegisterDirective(%q): expected an error", name)
+		}
+	}
+	if err := RegisterParser("%Y", func(string) (int, error) { return 0, nil }); err == nil {
+		t.Error("RegisterParser: expected an error for a built-in directive")
+	}
+
+	if errRegisterJ != nil {
+		t.Fatal(errRegisterJ)
+	}
+	if _, err := Parse("%J", "J"); err == nil {
+		t.Error("Parse: expected an error for a custom directive without parser")
+	}
+}
+
+// Run with -race to check that concurrent use is free of data races.
+func TestConcurrentUse(t *testing.T) {
+	if errRegisterK != nil {
+		t.Fatal(errRegisterK)
+	}
+	dt := time.Date(2021, time.April, 20, 15, 4, 5, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				// Distinct formats also exercise the caches.
+				format := "%Y-%m-%d %H:%M:%S %K" + strings.Repeat("%n", (i*200+j)%16)
+				s, err := Format(format, dt)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := Parse(format, s); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := ToNative("%Y-%m-%d" + strings.Repeat("%t", j%16)); err != nil {
+					t.Error(err)
+					return
+				}
+				if err := RegisterParser("%K", parseFiscalWeek); err != nil {
+					t.Error(err)
+					return
+				}
+				if err := RegisterDirective("%K", fiscalWeek); err == nil {
+					t.Error("RegisterDirective: expected an error for a directive registered twice")
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSetZoneNameFunc(t *testing.T) {
+	brt := time.FixedZone("", -3*3600)
+	dt := time.Date(2021, 3, 4, 5, 6, 7, 0, brt)
+	if s, err := Format("%H:%M %Z", dt); err != nil || s != "05:06 -0300" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "05:06 -0300")
+	}
+
+	SetZoneNameFunc(func(t time.Time) string {
+		if _, offset := t.Zone(); offset == -3*3600 {
+			return "BRT"
+		}
+		return ""
+	})
+	defer SetZoneNameFunc(nil)
+
+	tests := []struct {
+		dt       time.Time
+		expected string
+	}{
+		{dt, "05:06 BRT"},
+		// An empty name falls back to the default.
+		{time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), "05:06 UTC"},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, test := range tests {
+				if s, err := Format("%H:%M %Z", test.dt); err != nil || s != test.expected {
+					t.Errorf("Given: %v, %v, expected: %v", s, err, test.expected)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s, err := Format("%+", dt); err != nil || s != "Thu Mar  4 05:06:07 BRT 2021" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "Thu Mar  4 05:06:07 BRT 2021")
+	}
+
+	SetZoneNameFunc(nil)
+	if s, err := Format("%H:%M %Z", dt); err != nil || s != "05:06 -0300" {
+		t.Errorf("Given: %v, %v, expected: %v", s, err, "05:06 -0300")
+	}
+}
@@ -0,0 +1,45 @@
+package ctimefmt
+
+import "unicode/utf8"
+
+// nextDirective returns the bounds of the first directive in format at or
+// after offset i, or -1, -1 if there is none. A directive is a % followed
+// by an optional flag (-, _ or ^) or colons, which the # flag may precede,
+// a width, an E or O modifier and the conversion character, which can be
+// any character but a new-line.
+func nextDirective(format string, i int) (int, int) {
+	for ; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		j := i + 1
+		switch {
+		case j < len(format) && (format[j] == '-' || format[j] == '_' || format[j] == '^'):
+			j++
+		default:
+			if j < len(format) && format[j] == '#' {
+				j++
+			}
+			for j < len(format) && format[j] == ':' {
+				j++
+			}
+		}
+		for j < len(format) && '0' <= format[j] && format[j] <= '9' {
+			j++
+		}
+		if j < len(format) && (format[j] == 'E' || format[j] == 'O') {
+			j++
+		}
+
+		if j < len(format) && format[j] != '\n' {
+			_, n := utf8.DecodeRuneInString(format[j:])
+			return i, j + n
+		}
+		if j > i+1 {
+			// The last of the optional characters is the conversion
+			// character.
+			return i, j
+		}
+	}
+	return -1, -1
+}
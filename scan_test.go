@@ -0,0 +1,84 @@
+package ctimefmt
+
+import (
+	"regexp"
+	"testing"
+)
+
+// directiveRegexp is the regular expression nextDirective implements.
+var directiveRegexp = regexp.MustCompile(`%(?:[-_^]|#?:*)?\d*[EO]?.`)
+
+func scanDirectives(format string) [][]int {
+	var locs [][]int
+	for start, end := nextDirective(format, 0); start >= 0; start, end = nextDirective(format, end) {
+		locs = append(locs, []int{start, end})
+	}
+	return locs
+}
+
+func TestNextDirective(t *testing.T) {
+	formats := []string{
+		"", "%", "%%", "%%%", "100%", "%Y-%m-%d %H:%M:%S.%f", "%-d %_m %^B", "%:z %::z %:::z %::",
+		"%3f %10f %-5d", "%Ey %Od %E %O", "%-", "%5", "%:", "%E", "%-\n", "%5E\n", "%\n%Y",
+		"%ü %\xff", "abc", "%-Od %_3Ey", "%#z %#:z %#", "%#-d %#\n %##",
+	}
+	for _, format := range formats {
+		given, expected := scanDirectives(format), directiveRegexp.FindAllStringIndex(format, -1)
+		if len(given) != len(expected) {
+			t.Errorf("%q: given: %v, expected: %v", format, given, expected)
+			continue
+		}
+		for i := range given {
+			if given[i][0] != expected[i][0] || given[i][1] != expected[i][1] {
+				t.Errorf("%q: given: %v, expected: %v", format, given, expected)
+				break
+			}
+		}
+	}
+}
+
+// A %% always consumes a pair of percent signs, from left to right.
+func TestScanPercent(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{"%%", []string{"%%"}},
+		{"%%%", []string{"%%"}},
+		{"%%Y", []string{"%%"}},
+		{"%%%Y", []string{"%%", "%Y"}},
+		{"%%%%Y", []string{"%%", "%%"}},
+		{"%Y%%", []string{"%Y", "%%"}},
+	}
+	for _, test := range tests {
+		var given []string
+		for _, loc := range scanDirectives(test.format) {
+			given = append(given, test.format[loc[0]:loc[1]])
+		}
+		if len(given) != len(test.expected) {
+			t.Errorf("%q: given: %q, expected: %q", test.format, given, test.expected)
+			continue
+		}
+		for i := range given {
+			if given[i] != test.expected[i] {
+				t.Errorf("%q: given: %q, expected: %q", test.format, given, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkScanDirectives(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for start, end := nextDirective(format1, 0); start >= 0; start, end = nextDirective(format1, end) {
+		}
+	}
+}
+
+func BenchmarkScanDirectivesRegexp(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		directiveRegexp.FindAllStringIndex(format1, -1)
+	}
+}